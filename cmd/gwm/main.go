@@ -3,20 +3,44 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/example/gwm/internal/app/usecase"
 	"github.com/example/gwm/internal/domain"
+	"github.com/example/gwm/internal/infra/archive"
 	"github.com/example/gwm/internal/infra/config"
 	"github.com/example/gwm/internal/infra/fs"
 	"github.com/example/gwm/internal/infra/git"
+	"github.com/example/gwm/internal/infra/hook"
+	"github.com/example/gwm/internal/infra/launcher"
+	"github.com/example/gwm/internal/infra/port"
+	"github.com/example/gwm/internal/infra/proc"
 	"github.com/example/gwm/internal/infra/setting"
-	tmuxinfra "github.com/example/gwm/internal/infra/tmux"
+	"github.com/example/gwm/internal/infra/shell"
+	"github.com/example/gwm/internal/infra/state"
 	"github.com/example/gwm/internal/interface/cli"
 	"github.com/example/gwm/internal/interface/tui"
 )
 
 func main() {
-	repoDir, err := os.Getwd()
+	args, err := applyGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	// shell-init は .bashrc などリポジトリ外から呼ばれる。
+	if len(args) > 0 && args[0] == "shell-init" {
+		app := cli.App{ShellInit: shell.InitScript}
+		os.Exit(app.Run(args))
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	// サブディレクトリや linked worktree から実行してもメインの checkout を基準にする。
+	repoDir, err := git.FindRepoRoot(cwd)
 	if err != nil {
 		fmt.Println("error:", err)
 		os.Exit(1)
@@ -29,23 +53,115 @@ func main() {
 		os.Exit(1)
 	}
 	configSvc := domain.NewConfigService(cfgRepo, repoDir)
-	wtClient := git.NewWorktreeClient(repoDir)
+	wtClient := git.NewWorktreeClient(repoDir, settings)
 	fileOps := fs.NewOperator(repoDir)
-	sessionLauncher := tmuxinfra.NewLauncher(settings)
+	hookSvc := domain.NewHookService(cfgRepo, hook.NewRunner(os.Stderr), repoDir)
+	stateRepo := state.NewStore(repoDir)
+	allocator := domain.NewAllocatorService(stateRepo, port.NewChecker(), settings)
+	usage := domain.NewUsageService(stateRepo)
+	sessionNames := domain.NewSessionNameService(stateRepo, settings, repoDir)
+	sessionLauncher, err := launcher.New(settings, sessionNames)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
 
+	archives := archive.NewStore(repoDir)
+	createUC := &usecase.CreateInteractor{
+		Worktrees: wtClient,
+		Config:    cfgRepo,
+		FileOps:   fileOps,
+		Launcher:  sessionLauncher,
+		Hooks:     hookSvc,
+		Allocator: allocator,
+		Usage:     usage,
+		Sessions:  sessionNames,
+		RepoDir:   repoDir,
+	}
+	// restore とダッシュボードは作成後も処理を続けるので、セッションは開かない。
+	createNoSession := *createUC
+	createNoSession.Launcher = nil
+	removeUC := &usecase.RemoveInteractor{
+		Worktrees: wtClient,
+		Launcher:  sessionLauncher,
+		Hooks:     hookSvc,
+		Allocator: allocator,
+		Usage:     usage,
+		Sessions:  sessionNames,
+		Processes: proc.NewFinder(),
+		Archives:  archives,
+		Config:    cfgRepo,
+		FileOps:   fileOps,
+	}
+	renameUC := &usecase.RenameInteractor{
+		Worktrees: wtClient,
+		Launcher:  sessionLauncher,
+		Allocator: allocator,
+		Usage:     usage,
+		Sessions:  sessionNames,
+	}
+	listUC := &usecase.ListInteractor{Worktrees: wtClient, Launcher: sessionLauncher, Usage: usage}
+	cdUC := &usecase.CdInteractor{Worktrees: wtClient, Launcher: sessionLauncher, Hooks: hookSvc, Allocator: allocator, Usage: usage}
+	sessionLister, _ := sessionLauncher.(domain.SessionLister)
+	sessionUC := &usecase.SessionInteractor{
+		Worktrees: wtClient,
+		Launcher:  sessionLauncher,
+		Lister:    sessionLister,
+		Names:     sessionNames,
+		RepoDir:   repoDir,
+	}
+	picker := tui.Picker{Preview: listUC.Preview}
+	dashboard := tui.Dashboard{
+		List:    listUC,
+		Create:  &createNoSession,
+		Remove:  removeUC,
+		Rename:  renameUC,
+		Cd:      cdUC,
+		Session: sessionUC,
+		Editor:  settings.Editor,
+	}
 	app := cli.App{
-		Create: &usecase.CreateInteractor{
-			Worktrees: wtClient,
-			Config:    cfgRepo,
-			FileOps:   fileOps,
-			Launcher:  sessionLauncher,
-		},
-		Config: &usecase.ConfigInteractor{Service: configSvc},
-		Cd:     &usecase.CdInteractor{Worktrees: wtClient, Launcher: sessionLauncher},
-		Remove: &usecase.RemoveInteractor{Worktrees: wtClient, Launcher: sessionLauncher},
-		Select: tui.SelectWorktree,
-	}
-
-	code := app.Run(os.Args[1:])
+		Create:     createUC,
+		Config:     &usecase.ConfigInteractor{Service: configSvc, FileOps: fileOps},
+		Cd:         cdUC,
+		Remove:     removeUC,
+		Rename:     renameUC,
+		Restore:    &usecase.RestoreInteractor{Worktrees: wtClient, Archives: archives, Create: &createNoSession},
+		Prune:      &usecase.PruneInteractor{Worktrees: wtClient, Remove: removeUC, Usage: usage},
+		List:       listUC,
+		Env:        &usecase.EnvInteractor{Worktrees: wtClient, Allocator: allocator, RepoDir: repoDir},
+		Select:     picker.Select,
+		SelectMany: picker.SelectMany,
+		Session:    sessionUC,
+		ShellInit:  shell.InitScript,
+		UI:         dashboard.Run,
+	}
+
+	code := app.Run(args)
 	os.Exit(code)
 }
+
+// applyGlobalFlags handles options placed before the command.
+// `-C <dir>` changes the working directory first, like git.
+func applyGlobalFlags(args []string) ([]string, error) {
+	for len(args) > 0 {
+		switch {
+		case args[0] == "-C":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("-C requires a directory")
+			}
+			if err := os.Chdir(args[1]); err != nil {
+				return nil, err
+			}
+			args = args[2:]
+		case strings.HasPrefix(args[0], "-C"):
+			if err := os.Chdir(strings.TrimPrefix(args[0], "-C")); err != nil {
+				return nil, err
+			}
+			args = args[1:]
+		default:
+			return args, nil
+		}
+	}
+	return args, nil
+}
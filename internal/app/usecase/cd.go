@@ -9,6 +9,9 @@ import (
 type CdInteractor struct {
 	Worktrees domain.WorktreeService
 	Launcher  domain.SessionLauncher
+	Hooks     *domain.HookService
+	Allocator *domain.AllocatorService
+	Usage     *domain.UsageService
 }
 
 func (u *CdInteractor) List() ([]domain.WorktreeInfo, error) {
@@ -16,9 +19,32 @@ func (u *CdInteractor) List() ([]domain.WorktreeInfo, error) {
 }
 
 // Launch opens the selected worktree via configured launcher (tmux or fallback).
-func (u *CdInteractor) Launch(wt domain.WorktreeInfo) error {
+// opts.Target opens it inside the current session instead of its own one;
+// opts.Detach only starts the session and skips Enter.
+func (u *CdInteractor) Launch(wt domain.WorktreeInfo, opts domain.LaunchOptions) error {
 	if u.Launcher == nil {
 		return fmt.Errorf("no session launcher configured")
 	}
-	return u.Launcher.Launch(wt)
+	if !opts.Detach {
+		if err := u.Enter(wt); err != nil {
+			return err
+		}
+	}
+	return u.Launcher.Launch(wt, opts)
+}
+
+// Enter runs the on-enter hooks and records the worktree as used, without
+// launching a session.
+func (u *CdInteractor) Enter(wt domain.WorktreeInfo) error {
+	// フックの出力はランナーがそのまま流すので、ここではメッセージを捨てる。
+	hc := domain.HookContext{Branch: wt.Branch, WorktreePath: wt.Path}
+	if alloc, ok, err := u.Allocator.Get(wt.Branch); err == nil && ok {
+		hc.Allocation = alloc
+	}
+	if _, err := u.Hooks.Run(domain.HookOnEnter, hc); err != nil {
+		return err
+	}
+	// 最終利用時刻の記録失敗で移動を止めることはしない。
+	_ = u.Usage.Touch(wt.Branch)
+	return nil
 }
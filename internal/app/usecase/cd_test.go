@@ -9,22 +9,27 @@ import (
 
 type mockLauncher struct {
 	called bool
+	opts   domain.LaunchOptions
 	err    error
 }
 
-func (m *mockLauncher) Launch(domain.WorktreeInfo) error {
+func (m *mockLauncher) Launch(_ domain.WorktreeInfo, opts domain.LaunchOptions) error {
 	m.called = true
+	m.opts = opts
 	return m.err
 }
 
 func (m *mockLauncher) Kill(domain.WorktreeInfo) error { return nil }
 
+func (m *mockLauncher) HasSession(domain.WorktreeInfo) (bool, error) { return false, nil }
+func (m *mockLauncher) Windows(domain.WorktreeInfo) (int, error)     { return 0, nil }
+
 func TestCdInteractorLaunch(t *testing.T) {
 	wt := domain.WorktreeInfo{Path: "/tmp", Branch: "feature/foo"}
 
 	t.Run("no launcher", func(t *testing.T) {
 		u := &CdInteractor{}
-		if err := u.Launch(wt); err == nil {
+		if err := u.Launch(wt, domain.LaunchOptions{}); err == nil {
 			t.Fatalf("expected error when launcher is nil")
 		}
 	})
@@ -32,7 +37,7 @@ func TestCdInteractorLaunch(t *testing.T) {
 	t.Run("launcher called", func(t *testing.T) {
 		ml := &mockLauncher{}
 		u := &CdInteractor{Launcher: ml}
-		if err := u.Launch(wt); err != nil {
+		if err := u.Launch(wt, domain.LaunchOptions{}); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 		if !ml.called {
@@ -44,7 +49,7 @@ func TestCdInteractorLaunch(t *testing.T) {
 		want := errors.New("launch failed")
 		ml := &mockLauncher{err: want}
 		u := &CdInteractor{Launcher: ml}
-		if err := u.Launch(wt); !errors.Is(err, want) {
+		if err := u.Launch(wt, domain.LaunchOptions{}); !errors.Is(err, want) {
 			t.Fatalf("expected %v, got %v", want, err)
 		}
 	})
@@ -6,6 +6,14 @@ import (
 
 type ConfigInteractor struct {
 	Service *domain.ConfigService
+	// FileOps expands glob entries for List with expansion (optional).
+	FileOps domain.FileOperator
+}
+
+// ConfigListItem is a config entry together with the paths it currently covers.
+type ConfigListItem struct {
+	domain.ConfigEntry
+	Matches []string `json:"matches"`
 }
 
 func (u *ConfigInteractor) Add(entry domain.ConfigEntry) error {
@@ -16,6 +24,29 @@ func (u *ConfigInteractor) List() ([]domain.ConfigEntry, error) {
 	return u.Service.List()
 }
 
+// ListExpanded lists entries along with what each one expands to right now.
+func (u *ConfigInteractor) ListExpanded() ([]ConfigListItem, error) {
+	entries, err := u.Service.List()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ConfigListItem, len(entries))
+	for i, e := range entries {
+		items[i] = ConfigListItem{ConfigEntry: e, Matches: []string{}}
+		if u.FileOps == nil {
+			continue
+		}
+		matches, err := u.FileOps.Expand(e)
+		if err != nil {
+			return nil, err
+		}
+		if matches != nil {
+			items[i].Matches = matches
+		}
+	}
+	return items, nil
+}
+
 func (u *ConfigInteractor) Remove(path string) error {
 	return u.Service.Remove(path)
 }
@@ -1,13 +1,27 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/example/gwm/internal/domain"
 )
 
 type CreateInput struct {
 	Branch string
+	// Base is the ref to branch from (branch, tag, commit or remote branch).
+	// Empty means the default branch.
+	Base string
+	// Track sets Base as the upstream of the new branch.
+	Track bool
+	// KeepOnFailure leaves a partially created branch/worktree in place.
+	KeepOnFailure bool
+	// NoSession skips the launcher (and the on-enter hooks).
+	NoSession bool
+	// Detach starts the session with its layout but does not attach to it.
+	Detach bool
 }
 
 type CreateOutput struct {
@@ -20,44 +34,178 @@ type CreateInteractor struct {
 	Config    domain.ConfigRepository
 	FileOps   domain.FileOperator
 	Launcher  domain.SessionLauncher
+	Hooks     *domain.HookService
+	Allocator *domain.AllocatorService
+	Usage     *domain.UsageService
+	// Sessions forgets the session name stored for a session that is rolled back.
+	Sessions *domain.SessionNameService
+	// RepoDir is the main repository, exposed to template-mode files.
+	RepoDir string
 }
 
+// Execute creates the branch and worktree, deploys files and launches a session.
+// Completed steps are undone in reverse order when a later step fails,
+// unless KeepOnFailure is set.
 func (u *CreateInteractor) Execute(in CreateInput) (CreateOutput, error) {
 	var out CreateOutput
+	var tx transaction
 
+	err := u.execute(in, &out, &tx)
+	if err == nil {
+		return out, nil
+	}
+	if in.KeepOnFailure {
+		if len(tx.steps) > 0 {
+			out.Messages = append(out.Messages, "partial state kept (--keep-on-failure)")
+		}
+		return out, err
+	}
+	out.Messages = append(out.Messages, tx.rollback()...)
+	if len(tx.steps) > 0 {
+		out.Worktree = ""
+	}
+	return out, err
+}
+
+func (u *CreateInteractor) execute(in CreateInput, out *CreateOutput, tx *transaction) error {
 	exists, err := u.Worktrees.BranchExists(in.Branch)
 	if err != nil {
-		return out, err
+		return err
+	}
+	if exists && (in.Base != "" || in.Track) {
+		return fmt.Errorf("branch %s already exists; --base/--track cannot be applied", in.Branch)
 	}
 	if !exists {
-		if err := u.Worktrees.CreateBranch(in.Branch); err != nil {
-			return out, fmt.Errorf("branch create failed: %w", err)
+		base, err := u.Worktrees.CreateBranch(in.Branch, in.Base, in.Track)
+		if err != nil {
+			return fmt.Errorf("branch create failed: %w", err)
 		}
-		out.Messages = append(out.Messages, "branch created")
+		msg := "branch created from " + base
+		if in.Track {
+			msg += " (tracking " + base + ")"
+		}
+		out.Messages = append(out.Messages, msg)
+		// 作成直後のブランチは未マージ扱いになり得るので -D で消す。
+		tx.record("branch deleted: "+in.Branch, func() error {
+			return u.Worktrees.DeleteBranch(in.Branch, true)
+		})
 	}
 
 	path, err := u.Worktrees.AddWorktree(in.Branch)
 	if err != nil {
-		return out, err
+		return err
 	}
 	out.Worktree = path
 	out.Messages = append(out.Messages, "worktree added at "+path)
+	// 展開済みファイルは worktree ごと消えるので、個別の取り消しは不要。
+	tx.record("worktree removed: "+path, func() error {
+		_, err := u.Worktrees.RemoveWorktree(in.Branch, true)
+		return err
+	})
+
+	alloc, err := u.Allocator.Allocate(in.Branch, path)
+	if err != nil {
+		return fmt.Errorf("allocation failed: %w", err)
+	}
+	if alloc.Index > 0 {
+		out.Messages = append(out.Messages, fmt.Sprintf("allocated index %d (ports %d-%d)", alloc.Index, alloc.Port, alloc.Port+alloc.PortCount-1))
+		tx.record(fmt.Sprintf("allocation released: index %d", alloc.Index), func() error {
+			return u.Allocator.Release(in.Branch)
+		})
+	}
 
 	entries, err := u.Config.Load()
 	if err != nil {
-		return out, err
+		return err
 	}
-	if err := u.FileOps.Deploy(entries, path); err != nil {
-		return out, err
+	vars := u.templateVars(in.Branch, path, alloc)
+	if err := u.FileOps.Deploy(entries, path, vars); err != nil {
+		return err
 	}
 	out.Messages = append(out.Messages, fmt.Sprintf("%d file(s) deployed", len(entries)))
 
-	if u.Launcher != nil {
+	hc := domain.HookContext{Branch: in.Branch, WorktreePath: path, Allocation: alloc}
+	msgs, err := u.Hooks.Run(domain.HookPostCreate, hc)
+	out.Messages = append(out.Messages, msgs...)
+	if err != nil {
+		return err
+	}
+
+	if u.Launcher != nil && !in.NoSession {
+		// detach では worktree に入らないので on-enter は流さない。
+		if !in.Detach {
+			// Launch は tmux attach などでプロセスを置き換えることがあるため on-enter は先に流す。
+			msgs, err := u.Hooks.Run(domain.HookOnEnter, hc)
+			out.Messages = append(out.Messages, msgs...)
+			if err != nil {
+				return err
+			}
+			_ = u.Usage.Touch(in.Branch)
+		}
 		wt := domain.WorktreeInfo{Branch: in.Branch, Path: path}
-		if err := u.Launcher.Launch(wt); err != nil {
-			return out, err
+		// attach に失敗してもセッション自体は作られている場合がある。
+		tx.record("session killed", func() error {
+			if err := u.Launcher.Kill(wt); err != nil {
+				return err
+			}
+			return u.Sessions.Forget(wt)
+		})
+		err := u.Launcher.Launch(wt, domain.LaunchOptions{Detach: in.Detach})
+		if errors.Is(err, domain.ErrDetachUnsupported) {
+			// 裏で動かせるセッションが無いだけで、worktree は作れている。
+			out.Messages = append(out.Messages, "no background session with this launcher; worktree created")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if in.Detach {
+			out.Messages = append(out.Messages, "session started in the background")
+		} else {
+			out.Messages = append(out.Messages, "session launched")
+		}
+	}
+	return nil
+}
+
+func (u *CreateInteractor) templateVars(branch, path string, alloc domain.Allocation) domain.TemplateVars {
+	return domain.TemplateVars{
+		Repo:         filepath.Base(u.RepoDir),
+		RepoDir:      u.RepoDir,
+		Branch:       strings.TrimPrefix(branch, "refs/heads/"),
+		BranchSlug:   domain.BranchSlug(branch),
+		WorktreePath: path,
+		Index:        alloc.Index,
+		Port:         alloc.Port,
+		PortCount:    alloc.PortCount,
+	}
+}
+
+// transaction records undo actions for completed create steps.
+type transaction struct {
+	steps []rollbackStep
+}
+
+type rollbackStep struct {
+	name string
+	undo func() error
+}
+
+func (t *transaction) record(name string, undo func() error) {
+	t.steps = append(t.steps, rollbackStep{name: name, undo: undo})
+}
+
+// rollback undoes the recorded steps in reverse order and reports each result.
+// A failing undo does not stop the remaining ones.
+func (t *transaction) rollback() []string {
+	var msgs []string
+	for i := len(t.steps) - 1; i >= 0; i-- {
+		step := t.steps[i]
+		if err := step.undo(); err != nil {
+			msgs = append(msgs, fmt.Sprintf("rollback failed (%s): %v", step.name, err))
+			continue
 		}
-		out.Messages = append(out.Messages, "tmux session launched")
+		msgs = append(msgs, "rolled back: "+step.name)
 	}
-	return out, nil
+	return msgs
 }
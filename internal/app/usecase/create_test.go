@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+type createWorktreeStub struct {
+	fakeWorktreeService
+	exists      bool
+	createdFrom string
+	track       bool
+	undo        []string
+}
+
+func (s *createWorktreeStub) BranchExists(string) (bool, error) { return s.exists, nil }
+func (s *createWorktreeStub) CreateBranch(branch, base string, track bool) (string, error) {
+	if base == "" {
+		base = "main"
+	}
+	s.createdFrom = base
+	s.track = track
+	return base, nil
+}
+func (s *createWorktreeStub) AddWorktree(branch string) (string, error) {
+	return "/tmp/worktrees/" + branch, nil
+}
+func (s *createWorktreeStub) RemoveWorktree(branch string, force bool) (string, error) {
+	s.undo = append(s.undo, "remove "+branch)
+	return "/tmp/worktrees/" + branch, nil
+}
+func (s *createWorktreeStub) DeleteBranch(branch string, force bool) error {
+	s.undo = append(s.undo, "delete "+branch)
+	return nil
+}
+
+type emptyConfigRepo struct{}
+
+func (emptyConfigRepo) Load() ([]domain.ConfigEntry, error) { return nil, nil }
+func (emptyConfigRepo) Save([]domain.ConfigEntry) error     { return nil }
+
+type noopFileOps struct{}
+
+func (noopFileOps) Deploy([]domain.ConfigEntry, string, domain.TemplateVars) error { return nil }
+func (noopFileOps) Expand(e domain.ConfigEntry) ([]string, error)                  { return []string{e.Path}, nil }
+
+type failingFileOps struct{}
+
+func (failingFileOps) Deploy([]domain.ConfigEntry, string, domain.TemplateVars) error {
+	return errors.New("disk full")
+}
+func (failingFileOps) Expand(domain.ConfigEntry) ([]string, error) { return nil, nil }
+
+func TestCreateInteractorUsesBase(t *testing.T) {
+	wt := &createWorktreeStub{}
+	u := &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: noopFileOps{}}
+
+	out, err := u.Execute(CreateInput{Branch: "feature/foo", Base: "origin/release", Track: true})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if wt.createdFrom != "origin/release" || !wt.track {
+		t.Fatalf("unexpected CreateBranch call: base=%s track=%v", wt.createdFrom, wt.track)
+	}
+	if len(out.Messages) == 0 || !strings.Contains(out.Messages[0], "origin/release") {
+		t.Fatalf("base not reported: %v", out.Messages)
+	}
+}
+
+func TestCreateInteractorRejectsBaseForExistingBranch(t *testing.T) {
+	wt := &createWorktreeStub{exists: true}
+	u := &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: noopFileOps{}}
+
+	if _, err := u.Execute(CreateInput{Branch: "feature/foo", Base: "v1.0"}); err == nil {
+		t.Fatalf("expected error when branch exists and base given")
+	}
+}
+
+func TestCreateInteractorRollsBackOnFailure(t *testing.T) {
+	wt := &createWorktreeStub{}
+	u := &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: failingFileOps{}}
+
+	out, err := u.Execute(CreateInput{Branch: "feature"})
+	if err == nil {
+		t.Fatalf("expected deploy error")
+	}
+	if want := []string{"remove feature", "delete feature"}; !reflect.DeepEqual(wt.undo, want) {
+		t.Fatalf("undo = %v, want %v", wt.undo, want)
+	}
+	if out.Worktree != "" {
+		t.Fatalf("worktree should be cleared after rollback, got %q", out.Worktree)
+	}
+	rolled := 0
+	for _, m := range out.Messages {
+		if strings.HasPrefix(m, "rolled back: ") {
+			rolled++
+		}
+	}
+	if rolled != 2 {
+		t.Fatalf("expected 2 rollback messages, got %v", out.Messages)
+	}
+}
+
+func TestCreateInteractorKeepOnFailure(t *testing.T) {
+	wt := &createWorktreeStub{}
+	u := &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: failingFileOps{}}
+
+	if _, err := u.Execute(CreateInput{Branch: "feature", KeepOnFailure: true}); err == nil {
+		t.Fatalf("expected deploy error")
+	}
+	if len(wt.undo) != 0 {
+		t.Fatalf("nothing should be undone with KeepOnFailure, got %v", wt.undo)
+	}
+}
+
+func TestCreateInteractorSessionModes(t *testing.T) {
+	ml := &mockLauncher{}
+	u := &CreateInteractor{Worktrees: &createWorktreeStub{}, Config: emptyConfigRepo{}, FileOps: noopFileOps{}, Launcher: ml}
+
+	if _, err := u.Execute(CreateInput{Branch: "feature/foo", NoSession: true}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if ml.called {
+		t.Fatal("--no-session should not call the launcher")
+	}
+	out, err := u.Execute(CreateInput{Branch: "feature/bar", Detach: true})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !ml.called || !ml.opts.Detach {
+		t.Fatalf("launcher should be called detached: called=%v opts=%+v", ml.called, ml.opts)
+	}
+	if last := out.Messages[len(out.Messages)-1]; !strings.Contains(last, "background") {
+		t.Fatalf("detached launch not reported: %v", out.Messages)
+	}
+}
+
+func TestCreateInteractorDetachWithoutBackgroundSessions(t *testing.T) {
+	wt := &createWorktreeStub{}
+	ml := &mockLauncher{err: domain.ErrDetachUnsupported}
+	u := &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: noopFileOps{}, Launcher: ml}
+
+	out, err := u.Execute(CreateInput{Branch: "feature", Detach: true})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(wt.undo) != 0 || out.Worktree != "/tmp/worktrees/feature" {
+		t.Fatalf("worktree should be kept: undo=%v worktree=%q", wt.undo, out.Worktree)
+	}
+	for _, m := range out.Messages {
+		if strings.Contains(m, "session started") {
+			t.Fatalf("no session was started, got %v", out.Messages)
+		}
+	}
+	if last := out.Messages[len(out.Messages)-1]; !strings.Contains(last, "no background session") {
+		t.Fatalf("unsupported detach not reported: %v", out.Messages)
+	}
+}
+
+func TestCreateInteractorRollsBackWhenLaunchFails(t *testing.T) {
+	wt := &createWorktreeStub{}
+	ml := &mockLauncher{err: errors.New("tmux: command not found")}
+	state := &memoryStateRepo{}
+	names := domain.NewSessionNameService(state, domain.Settings{}, "/tmp")
+	// ランチャーが名前を記録した後で失敗した場合。
+	if err := names.Remember(domain.WorktreeInfo{Branch: "feature", Path: "/tmp/worktrees/feature"}, "gwm-feature"); err != nil {
+		t.Fatal(err)
+	}
+	u := &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: noopFileOps{}, Launcher: ml, Sessions: names}
+
+	out, err := u.Execute(CreateInput{Branch: "feature"})
+	if err == nil {
+		t.Fatal("expected the launch error")
+	}
+	if want := []string{"remove feature", "delete feature"}; !reflect.DeepEqual(wt.undo, want) {
+		t.Fatalf("undo = %v, want %v", wt.undo, want)
+	}
+	if out.Worktree != "" {
+		t.Fatalf("worktree should be cleared after rollback, got %q", out.Worktree)
+	}
+	if len(state.st.Sessions) != 0 {
+		t.Fatalf("the rolled back session name should be forgotten: %+v", state.st.Sessions)
+	}
+
+	wt = &createWorktreeStub{}
+	u.Worktrees = wt
+	if _, err := u.Execute(CreateInput{Branch: "feature", KeepOnFailure: true}); err == nil || len(wt.undo) != 0 {
+		t.Fatalf("--keep-on-failure should keep the worktree: err=%v undo=%v", err, wt.undo)
+	}
+}
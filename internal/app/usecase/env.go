@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// EnvInteractor reports the per-worktree variables (index, ports, paths).
+type EnvInteractor struct {
+	Worktrees domain.WorktreeService
+	Allocator *domain.AllocatorService
+	RepoDir   string
+}
+
+// Execute returns GWM_* variables for branch as KEY=value pairs.
+// Worktrees created before allocation existed get an allocation on demand.
+func (u *EnvInteractor) Execute(branch string) ([]string, error) {
+	list, err := u.Worktrees.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	wt := findWorktree(list, branch)
+	if wt == nil {
+		return nil, fmt.Errorf("worktree not found for branch %s", branch)
+	}
+	env := []string{
+		"GWM_BRANCH=" + strings.TrimPrefix(wt.Branch, "refs/heads/"),
+		"GWM_BRANCH_SLUG=" + domain.BranchSlug(wt.Branch),
+		"GWM_WORKTREE_PATH=" + wt.Path,
+		"GWM_REPO_ROOT=" + u.RepoDir,
+	}
+	// メインの checkout は番号 0 固定で、ポートを割り当てない。
+	if filepath.Clean(wt.Path) == filepath.Clean(u.RepoDir) {
+		return env, nil
+	}
+	alloc, err := u.Allocator.Allocate(wt.Branch, wt.Path)
+	if err != nil {
+		return nil, err
+	}
+	return append(env, alloc.Env()...), nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"github.com/example/gwm/internal/domain"
+)
+
+// ListInteractor collects worktrees together with their status for `gwm list`.
+type ListInteractor struct {
+	Worktrees domain.WorktreeService
+	Launcher  domain.SessionLauncher
+	Usage     *domain.UsageService
+}
+
+// Execute returns every worktree with status, session and last-used time filled.
+// Branch is returned without the refs/heads/ prefix for display.
+// Per-worktree failures (e.g. a missing directory) leave those fields empty.
+func (u *ListInteractor) Execute() ([]domain.WorktreeInfo, error) {
+	list, err := u.Worktrees.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	lastUsed, err := u.Usage.LastUsed()
+	if err != nil {
+		return nil, err
+	}
+	for i, wt := range list {
+		// bare と prunable (ディレクトリ消失) には作業ツリーが無い。
+		if !wt.Bare && !wt.Prunable {
+			if described, err := u.Worktrees.Describe(wt); err == nil {
+				wt = described
+			}
+		}
+		if u.Launcher != nil {
+			if has, err := u.Launcher.HasSession(wt); err == nil {
+				wt.HasSession = has
+			}
+		}
+		wt.Branch = wt.ShortBranch()
+		wt.LastUsed = lastUsed[wt.Branch]
+		list[i] = wt
+	}
+	return list, nil
+}
+
+// Preview collects the details the picker shows for one worktree. Parts that
+// cannot be read (e.g. a missing directory) are left empty.
+func (u *ListInteractor) Preview(wt domain.WorktreeInfo) (domain.WorktreePreview, error) {
+	p := domain.WorktreePreview{Worktree: wt}
+	if !wt.Bare && !wt.Prunable {
+		described, err := u.Worktrees.Describe(wt)
+		if err != nil {
+			return p, err
+		}
+		p.Worktree = described
+		if p.LastCommit, err = u.Worktrees.LastCommit(wt.Path); err != nil {
+			return p, err
+		}
+		if p.Status, err = u.Worktrees.ShortStatus(wt.Path); err != nil {
+			return p, err
+		}
+	}
+	if u.Launcher != nil {
+		if n, err := u.Launcher.Windows(wt); err == nil {
+			p.HasSession = n > 0
+			p.Windows = n
+		}
+	}
+	return p, nil
+}
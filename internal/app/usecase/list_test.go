@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+type previewWorktrees struct {
+	fakeWorktreeService
+}
+
+func (previewWorktrees) Describe(wt domain.WorktreeInfo) (domain.WorktreeInfo, error) {
+	wt.Upstream, wt.Ahead = "origin/feature", 2
+	return wt, nil
+}
+func (previewWorktrees) LastCommit(string) (domain.Commit, error) {
+	return domain.Commit{Hash: "abc", Subject: "wip"}, nil
+}
+func (previewWorktrees) ShortStatus(string) ([]string, error) { return []string{"?? x"}, nil }
+
+type windowLauncher struct{ fakeLauncher }
+
+func (windowLauncher) Windows(domain.WorktreeInfo) (int, error) { return 2, nil }
+
+func TestListInteractorPreview(t *testing.T) {
+	u := &ListInteractor{Worktrees: &previewWorktrees{}, Launcher: &windowLauncher{}}
+
+	p, err := u.Preview(domain.WorktreeInfo{Branch: "feature", Path: "/tmp/wt"})
+	if err != nil {
+		t.Fatalf("Preview returned error: %v", err)
+	}
+	want := domain.WorktreePreview{
+		Worktree:   domain.WorktreeInfo{Branch: "feature", Path: "/tmp/wt", Upstream: "origin/feature", Ahead: 2},
+		LastCommit: domain.Commit{Hash: "abc", Subject: "wip"},
+		Status:     []string{"?? x"},
+		HasSession: true,
+		Windows:    2,
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Fatalf("Preview = %+v, want %+v", p, want)
+	}
+
+	// ディレクトリが消えた worktree は git を呼ばない。
+	p, err = u.Preview(domain.WorktreeInfo{Branch: "gone", Path: "/tmp/gone", Prunable: true})
+	if err != nil || p.LastCommit.Hash != "" || p.Status != nil {
+		t.Fatalf("prunable preview = %+v, %v", p, err)
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// PruneInput selects which worktrees `gwm prune` removes.
+type PruneInput struct {
+	// MergedInto is the ref branches must be merged into (default branch when empty).
+	MergedInto string
+	// StaleDays also selects worktrees untouched for this many days (0 disables).
+	StaleDays int
+	// DeleteBranch deletes the local branch (git branch -d) after removal.
+	DeleteBranch bool
+}
+
+// PruneCandidate is a worktree selected for removal and why.
+type PruneCandidate struct {
+	Worktree domain.WorktreeInfo
+	Reasons  []string
+}
+
+// PruneOutput reports per-worktree results.
+type PruneOutput struct {
+	Messages []string
+	Failed   int
+}
+
+// PruneInteractor removes merged, orphaned or stale worktrees.
+type PruneInteractor struct {
+	Worktrees domain.WorktreeService
+	Remove    *RemoveInteractor
+	Usage     *domain.UsageService
+	now       func() time.Time
+}
+
+// Plan lists the worktrees that would be removed. The main worktree, the
+// current one, the default branch, locked, detached and bare worktrees are
+// never selected.
+func (u *PruneInteractor) Plan(in PruneInput) ([]PruneCandidate, error) {
+	list, err := u.Worktrees.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := u.Worktrees.BranchStatuses(in.MergedInto)
+	if err != nil {
+		return nil, err
+	}
+	lastUsed, err := u.Usage.LastUsed()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if u.now != nil {
+		now = u.now
+	}
+	into := strings.TrimPrefix(in.MergedInto, "refs/heads/")
+
+	var candidates []PruneCandidate
+	for i, wt := range list {
+		// git worktree list は常にメインの worktree を先頭に返す。
+		if i == 0 || wt.IsCurrent || wt.Locked || wt.Bare || wt.Branch == "" {
+			continue
+		}
+		branch := wt.ShortBranch()
+		st, ok := statuses[branch]
+		// デフォルトブランチは自分自身にマージ済みに見えるので、別の worktree にあっても対象外。
+		if !ok || st.Default || (into != "" && branch == into) {
+			continue
+		}
+		var reasons []string
+		if st.Merged {
+			reasons = append(reasons, "merged")
+		}
+		if st.UpstreamGone {
+			reasons = append(reasons, "upstream gone")
+		}
+		if in.StaleDays > 0 {
+			last := st.LastCommit
+			if t := lastUsed[branch]; t.After(last) {
+				last = t
+			}
+			if !last.IsZero() && now().Sub(last) > time.Duration(in.StaleDays)*24*time.Hour {
+				reasons = append(reasons, fmt.Sprintf("untouched since %s", last.Format("2006-01-02")))
+			}
+		}
+		if wt.Prunable {
+			reasons = append(reasons, "directory missing")
+		}
+		if len(reasons) > 0 {
+			candidates = append(candidates, PruneCandidate{Worktree: wt, Reasons: reasons})
+		}
+	}
+	return candidates, nil
+}
+
+// Execute removes the candidates through RemoveInteractor (which also kills
+// sessions and runs hooks), optionally deletes their branches, and finally
+// runs `git worktree prune`. A failure does not stop the remaining removals.
+func (u *PruneInteractor) Execute(candidates []PruneCandidate, in PruneInput) PruneOutput {
+	var out PruneOutput
+	for _, c := range candidates {
+		branch := c.Worktree.ShortBranch()
+		res, err := u.Remove.Execute(RemoveInput{Branch: branch})
+		out.Messages = append(out.Messages, res.Messages...)
+		if err != nil {
+			out.Failed++
+			out.Messages = append(out.Messages, fmt.Sprintf("failed to remove %s: %v", branch, err))
+			continue
+		}
+		if in.DeleteBranch {
+			if err := u.Worktrees.DeleteBranch(branch, false); err != nil {
+				out.Failed++
+				out.Messages = append(out.Messages, fmt.Sprintf("failed to delete branch %s: %v", branch, err))
+				continue
+			}
+			out.Messages = append(out.Messages, "branch deleted: "+branch)
+		}
+	}
+	if err := u.Worktrees.PruneWorktrees(); err != nil {
+		out.Failed++
+		out.Messages = append(out.Messages, err.Error())
+	} else {
+		out.Messages = append(out.Messages, "git worktree prune done")
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+type pruneWorktreeStub struct {
+	fakeWorktreeService
+	list     []domain.WorktreeInfo
+	statuses map[string]domain.BranchStatus
+	deleted  []string
+	pruned   bool
+}
+
+func (s *pruneWorktreeStub) ListWorktrees() ([]domain.WorktreeInfo, error) { return s.list, nil }
+func (s *pruneWorktreeStub) BranchStatuses(string) (map[string]domain.BranchStatus, error) {
+	return s.statuses, nil
+}
+func (s *pruneWorktreeStub) DeleteBranch(branch string, force bool) error {
+	s.deleted = append(s.deleted, branch)
+	return nil
+}
+func (s *pruneWorktreeStub) PruneWorktrees() error {
+	s.pruned = true
+	return nil
+}
+
+func TestPruneInteractorPlan(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	wt := &pruneWorktreeStub{
+		list: []domain.WorktreeInfo{
+			{Branch: "refs/heads/main", Path: "/repo"},
+			{Branch: "refs/heads/merged", Path: "/repo/worktrees/merged"},
+			{Branch: "refs/heads/gone", Path: "/repo/worktrees/gone"},
+			{Branch: "refs/heads/old", Path: "/repo/worktrees/old"},
+			{Branch: "refs/heads/active", Path: "/repo/worktrees/active"},
+			{Branch: "refs/heads/locked", Path: "/repo/worktrees/locked", Locked: true},
+			{Branch: "refs/heads/here", Path: "/repo/worktrees/here", IsCurrent: true},
+		},
+		statuses: map[string]domain.BranchStatus{
+			"main":   {Merged: true, LastCommit: now},
+			"merged": {Merged: true, LastCommit: now},
+			"gone":   {UpstreamGone: true, LastCommit: now},
+			"old":    {LastCommit: now.AddDate(0, 0, -40)},
+			"active": {LastCommit: now.AddDate(0, 0, -1)},
+			"locked": {Merged: true},
+			"here":   {Merged: true},
+		},
+	}
+	u := &PruneInteractor{Worktrees: wt, now: func() time.Time { return now }}
+
+	got, err := u.Plan(PruneInput{StaleDays: 30})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	var branches []string
+	for _, c := range got {
+		branches = append(branches, c.Worktree.ShortBranch())
+	}
+	if want := []string{"merged", "gone", "old"}; !reflect.DeepEqual(branches, want) {
+		t.Fatalf("candidates = %v, want %v", branches, want)
+	}
+
+	got, err = u.Plan(PruneInput{})
+	if err != nil || len(got) != 2 {
+		t.Fatalf("without --stale-days expected 2 candidates, got %+v (%v)", got, err)
+	}
+}
+
+func TestPruneInteractorPlanKeepsDefaultBranch(t *testing.T) {
+	// main がメイン以外の worktree にチェックアウトされている。
+	wt := &pruneWorktreeStub{
+		list: []domain.WorktreeInfo{
+			{Branch: "refs/heads/develop", Path: "/repo"},
+			{Branch: "refs/heads/main", Path: "/repo/worktrees/main"},
+			{Branch: "refs/heads/merged", Path: "/repo/worktrees/merged"},
+		},
+		statuses: map[string]domain.BranchStatus{
+			"develop": {},
+			"main":    {Merged: true, Default: true},
+			"merged":  {Merged: true},
+		},
+	}
+	u := &PruneInteractor{Worktrees: wt}
+
+	got, err := u.Plan(PruneInput{})
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Worktree.ShortBranch() != "merged" {
+		t.Fatalf("default branch must not be pruned: %+v", got)
+	}
+}
+
+func TestPruneInteractorExecute(t *testing.T) {
+	wt := &pruneWorktreeStub{}
+	u := &PruneInteractor{Worktrees: wt, Remove: &RemoveInteractor{Worktrees: wt}}
+	candidates := []PruneCandidate{{Worktree: domain.WorktreeInfo{Branch: "refs/heads/merged"}}}
+
+	out := u.Execute(candidates, PruneInput{DeleteBranch: true})
+	if out.Failed != 0 {
+		t.Fatalf("unexpected failures: %v", out.Messages)
+	}
+	if wt.removedBranch != "merged" || !reflect.DeepEqual(wt.deleted, []string{"merged"}) || !wt.pruned {
+		t.Fatalf("unexpected calls: removed=%s deleted=%v pruned=%v", wt.removedBranch, wt.deleted, wt.pruned)
+	}
+}
@@ -4,14 +4,34 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/example/gwm/internal/domain"
 )
 
+// BranchDeletion selects what happens to the branch after its worktree is removed.
+type BranchDeletion string
+
+const (
+	// KeepBranch leaves the branch alone (default).
+	KeepBranch BranchDeletion = ""
+	// DeleteBranchSafe deletes with git branch -d and refuses unpushed commits.
+	DeleteBranchSafe BranchDeletion = "safe"
+	// DeleteBranchForce deletes with git branch -D without any check.
+	DeleteBranchForce BranchDeletion = "force"
+)
+
 // RemoveInput represents the parameters for deleting a worktree.
 type RemoveInput struct {
 	Branch string
 	Force  bool
+	// DeleteBranch also deletes the local branch.
+	DeleteBranch BranchDeletion
+	// DeleteRemote also deletes the branch's upstream on its remote.
+	DeleteRemote bool
+	// Archive snapshots uncommitted changes and untracked/managed files
+	// before removal (see RestoreInteractor).
+	Archive bool
 }
 
 // RemoveOutput describes the user-facing messages for the removal command.
@@ -23,6 +43,40 @@ type RemoveOutput struct {
 type RemoveInteractor struct {
 	Worktrees domain.WorktreeService
 	Launcher  domain.SessionLauncher
+	Hooks     *domain.HookService
+	Allocator *domain.AllocatorService
+	Usage     *domain.UsageService
+	// Sessions forgets the session name stored for the removed worktree.
+	Sessions  *domain.SessionNameService
+	Processes domain.ProcessFinder
+	// Archives, Config and FileOps are only needed for RemoveInput.Archive.
+	Archives domain.ArchiveRepository
+	Config   domain.ConfigRepository
+	FileOps  domain.FileOperator
+	now      func() time.Time
+}
+
+// Inspect reports what removing branch's worktree would discard, so callers
+// can ask for confirmation before Execute.
+func (u *RemoveInteractor) Inspect(branch string) (domain.RemovalInspection, error) {
+	list, err := u.Worktrees.ListWorktrees()
+	if err != nil {
+		return domain.RemovalInspection{}, err
+	}
+	target := findWorktree(list, branch)
+	if target == nil {
+		return domain.RemovalInspection{}, fmt.Errorf("worktree for branch %s not found", branch)
+	}
+	r, err := u.Worktrees.Inspect(*target)
+	if err != nil {
+		return r, err
+	}
+	if u.Processes != nil {
+		if r.Processes, err = u.Processes.ProcessesIn(target.Path); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
 }
 
 func (u *RemoveInteractor) Execute(in RemoveInput) (RemoveOutput, error) {
@@ -38,26 +92,162 @@ func (u *RemoveInteractor) Execute(in RemoveInput) (RemoveOutput, error) {
 		target = findWorktree(list, in.Branch)
 	}
 
+	// ブランチ削除の可否は worktree を消す前に判定しておく。
+	var remote, remoteName string
+	if in.DeleteRemote {
+		r, name, ok, err := u.Worktrees.Upstream(in.Branch)
+		if err != nil {
+			return out, err
+		}
+		if !ok {
+			return out, fmt.Errorf("branch %s has no upstream to delete", in.Branch)
+		}
+		remote, remoteName = r, name
+	}
+	if in.DeleteBranch == DeleteBranchSafe {
+		var ignore []string
+		if remote != "" {
+			// 消す予定の upstream にしかないコミットも未 push とみなす。
+			ignore = append(ignore, remote+"/"+remoteName)
+		}
+		n, err := u.Worktrees.UnpushedCommits(in.Branch, ignore...)
+		if err != nil {
+			return out, err
+		}
+		if n > 0 {
+			return out, fmt.Errorf("branch %s has %d unpushed commit(s); use --delete-branch=force to delete it anyway", in.Branch, n)
+		}
+	}
+
+	hc := domain.HookContext{Branch: in.Branch}
+	if target != nil {
+		hc.WorktreePath = target.Path
+	}
+	if alloc, ok, err := u.Allocator.Get(in.Branch); err == nil && ok {
+		hc.Allocation = alloc
+	}
+	msgs, err := u.Hooks.Run(domain.HookPreRemove, hc)
+	out.Messages = append(out.Messages, msgs...)
+	if err != nil {
+		return out, err
+	}
+
+	if in.Archive {
+		if target == nil {
+			return out, fmt.Errorf("worktree for branch %s not found", in.Branch)
+		}
+		p, err := u.archive(*target)
+		if err != nil {
+			return out, fmt.Errorf("archive failed: %w", err)
+		}
+		out.Messages = append(out.Messages, "archived: "+p)
+	}
+
 	path, err := u.Worktrees.RemoveWorktree(in.Branch, in.Force)
 	if err != nil {
 		return out, err
 	}
 	out.Messages = append(out.Messages, fmt.Sprintf("worktree removed: %s", path))
 
+	wt := domain.WorktreeInfo{Branch: in.Branch, Path: path}
+	if target != nil {
+		wt = *target
+	}
 	if u.Launcher != nil {
-		wt := domain.WorktreeInfo{Branch: in.Branch, Path: path}
-		if target != nil {
-			wt = *target
-		}
 		if err := u.Launcher.Kill(wt); err != nil {
 			return out, err
 		}
 		out.Messages = append(out.Messages, "session removed (if existed)")
 	}
+	if err := u.Sessions.Forget(wt); err != nil {
+		return out, err
+	}
+
+	if err := u.Allocator.Release(in.Branch); err != nil {
+		return out, err
+	}
+	if hc.Allocation.Index > 0 {
+		out.Messages = append(out.Messages, fmt.Sprintf("allocation released: index %d", hc.Allocation.Index))
+	}
+	if err := u.Usage.Forget(in.Branch); err != nil {
+		return out, err
+	}
+
+	if in.DeleteBranch != KeepBranch {
+		if err := u.Worktrees.DeleteBranch(in.Branch, in.DeleteBranch == DeleteBranchForce); err != nil {
+			return out, err
+		}
+		out.Messages = append(out.Messages, "branch deleted: "+strings.TrimPrefix(in.Branch, "refs/heads/"))
+	}
+	if remote != "" {
+		if err := u.Worktrees.DeleteRemoteBranch(remote, remoteName); err != nil {
+			return out, err
+		}
+		out.Messages = append(out.Messages, fmt.Sprintf("remote branch deleted: %s/%s", remote, remoteName))
+	}
+
+	hc.WorktreePath = path
+	msgs, err = u.Hooks.Run(domain.HookPostRemove, hc)
+	out.Messages = append(out.Messages, msgs...)
+	if err != nil {
+		return out, err
+	}
 
 	return out, nil
 }
 
+func (u *RemoveInteractor) archive(wt domain.WorktreeInfo) (string, error) {
+	if u.Archives == nil {
+		return "", errors.New("archive store not configured")
+	}
+	cs, err := u.Worktrees.CaptureChanges(wt.Path)
+	if err != nil {
+		return "", err
+	}
+	managed, err := u.managedFiles()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now
+	if u.now != nil {
+		now = u.now
+	}
+	snap := domain.Snapshot{
+		Manifest: domain.ArchiveManifest{
+			Branch:    wt.ShortBranch(),
+			Commit:    cs.Commit,
+			CreatedAt: now(),
+			Files:     append(cs.Untracked, managed...),
+		},
+		Patch: cs.Patch,
+	}
+	return u.Archives.Save(snap, wt.Path)
+}
+
+// managedFiles lists the copy/template entries gwm deployed. They are
+// usually git-ignored (.env など) and would not be in the diff otherwise.
+func (u *RemoveInteractor) managedFiles() ([]string, error) {
+	if u.Config == nil || u.FileOps == nil {
+		return nil, nil
+	}
+	entries, err := u.Config.Load()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if e.Mode == domain.ModeSymlink {
+			continue
+		}
+		paths, err := u.FileOps.Expand(e)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, paths...)
+	}
+	return files, nil
+}
+
 func findWorktree(list []domain.WorktreeInfo, branch string) *domain.WorktreeInfo {
 	normalized := branch
 	if !strings.HasPrefix(branch, "refs/heads/") {
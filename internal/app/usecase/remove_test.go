@@ -13,26 +13,73 @@ type fakeWorktreeService struct {
 	force         bool
 	path          string
 	err           error
+
+	remote        string
+	unpushed      int
+	ignored       []string
+	deletedBranch string
+	deleteForce   bool
+	deletedRemote string
 }
 
-func (f *fakeWorktreeService) BranchExists(string) (bool, error)  { return false, nil }
-func (f *fakeWorktreeService) CreateBranch(string) error          { return nil }
+func (f *fakeWorktreeService) BranchExists(string) (bool, error) { return false, nil }
+func (f *fakeWorktreeService) CreateBranch(string, string, bool) (string, error) {
+	return "main", nil
+}
+func (f *fakeWorktreeService) DeleteBranch(branch string, force bool) error {
+	f.deletedBranch = branch
+	f.deleteForce = force
+	return nil
+}
 func (f *fakeWorktreeService) AddWorktree(string) (string, error) { return "", nil }
 func (f *fakeWorktreeService) ListWorktrees() ([]domain.WorktreeInfo, error) {
 	return nil, nil
 }
+func (f *fakeWorktreeService) BranchStatuses(string) (map[string]domain.BranchStatus, error) {
+	return nil, nil
+}
+func (f *fakeWorktreeService) PruneWorktrees() error { return nil }
+func (f *fakeWorktreeService) Upstream(branch string) (string, string, bool, error) {
+	return f.remote, branch, f.remote != "", nil
+}
+func (f *fakeWorktreeService) UnpushedCommits(_ string, ignore ...string) (int, error) {
+	f.ignored = ignore
+	return f.unpushed, nil
+}
+func (f *fakeWorktreeService) DeleteRemoteBranch(remote, name string) error {
+	f.deletedRemote = remote + "/" + name
+	return nil
+}
+func (f *fakeWorktreeService) Inspect(wt domain.WorktreeInfo) (domain.RemovalInspection, error) {
+	return domain.RemovalInspection{Branch: wt.Branch, Path: wt.Path}, nil
+}
+func (f *fakeWorktreeService) CaptureChanges(string) (domain.ChangeSet, error) {
+	return domain.ChangeSet{}, nil
+}
+func (f *fakeWorktreeService) ApplyPatch(string, []byte, bool) error    { return nil }
+func (f *fakeWorktreeService) Head(string) (string, error)              { return "", nil }
+func (f *fakeWorktreeService) LastCommit(string) (domain.Commit, error) { return domain.Commit{}, nil }
+func (f *fakeWorktreeService) ShortStatus(string) ([]string, error)     { return nil, nil }
+func (f *fakeWorktreeService) Describe(wt domain.WorktreeInfo) (domain.WorktreeInfo, error) {
+	return wt, nil
+}
 func (f *fakeWorktreeService) RemoveWorktree(branch string, force bool) (string, error) {
 	f.removedBranch = branch
 	f.force = force
 	return f.path, f.err
 }
+func (f *fakeWorktreeService) RenameWorktree(domain.WorktreeInfo, string) (string, error) {
+	return f.path, f.err
+}
 
 type fakeLauncher struct {
 	killed []domain.WorktreeInfo
 	err    error
 }
 
-func (l *fakeLauncher) Launch(domain.WorktreeInfo) error { return nil }
+func (l *fakeLauncher) Launch(domain.WorktreeInfo, domain.LaunchOptions) error { return nil }
+func (l *fakeLauncher) HasSession(domain.WorktreeInfo) (bool, error)           { return false, nil }
+func (l *fakeLauncher) Windows(domain.WorktreeInfo) (int, error)               { return 0, nil }
 func (l *fakeLauncher) Kill(wt domain.WorktreeInfo) error {
 	l.killed = append(l.killed, wt)
 	return l.err
@@ -75,3 +122,132 @@ func TestRemoveInteractorKillError(t *testing.T) {
 		t.Fatalf("expected error when Kill fails")
 	}
 }
+
+type failingHooks struct{}
+
+func (failingHooks) LoadHooks() (domain.HookConfig, error) {
+	return domain.HookConfig{domain.HookPreRemove: {{Command: "false"}}}, nil
+}
+
+type failingRunner struct{}
+
+func (failingRunner) Run(domain.Hook, domain.HookContext) error { return errors.New("exit status 1") }
+
+func TestRemoveInteractorPreRemoveHookAborts(t *testing.T) {
+	wt := &fakeWorktreeService{path: "/tmp/worktrees/feature"}
+	u := &RemoveInteractor{
+		Worktrees: wt,
+		Hooks:     domain.NewHookService(failingHooks{}, failingRunner{}, "/tmp"),
+	}
+
+	if _, err := u.Execute(RemoveInput{Branch: "feature"}); err == nil {
+		t.Fatalf("expected error from pre-remove hook")
+	}
+	if wt.removedBranch != "" {
+		t.Fatalf("worktree should not be removed when pre-remove hook fails")
+	}
+}
+
+func TestRemoveInteractorDeleteBranchRefusesUnpushed(t *testing.T) {
+	wt := &fakeWorktreeService{path: "/tmp/worktrees/feature", unpushed: 2}
+	u := &RemoveInteractor{Worktrees: wt}
+
+	if _, err := u.Execute(RemoveInput{Branch: "feature", DeleteBranch: DeleteBranchSafe}); err == nil {
+		t.Fatal("expected refusal for unpushed commits")
+	}
+	if wt.removedBranch != "" || wt.deletedBranch != "" {
+		t.Fatalf("nothing should be removed: worktree=%q branch=%q", wt.removedBranch, wt.deletedBranch)
+	}
+
+	if _, err := u.Execute(RemoveInput{Branch: "feature", DeleteBranch: DeleteBranchForce}); err != nil {
+		t.Fatalf("force delete returned error: %v", err)
+	}
+	if wt.deletedBranch != "feature" || !wt.deleteForce {
+		t.Fatalf("unexpected delete: branch=%q force=%v", wt.deletedBranch, wt.deleteForce)
+	}
+}
+
+func TestRemoveInteractorDeleteRemote(t *testing.T) {
+	wt := &fakeWorktreeService{path: "/tmp/worktrees/feature", remote: "origin"}
+	u := &RemoveInteractor{Worktrees: wt}
+
+	out, err := u.Execute(RemoveInput{Branch: "feature", DeleteBranch: DeleteBranchSafe, DeleteRemote: true})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if !reflect.DeepEqual(wt.ignored, []string{"origin/feature"}) {
+		t.Fatalf("upstream should be ignored when counting unpushed commits, got %v", wt.ignored)
+	}
+	if wt.deletedBranch != "feature" || wt.deleteForce || wt.deletedRemote != "origin/feature" {
+		t.Fatalf("unexpected deletes: branch=%q force=%v remote=%q", wt.deletedBranch, wt.deleteForce, wt.deletedRemote)
+	}
+	if got := out.Messages[len(out.Messages)-1]; got != "remote branch deleted: origin/feature" {
+		t.Fatalf("unexpected last message %q", got)
+	}
+}
+
+func TestRemoveInteractorDeleteRemoteWithoutUpstream(t *testing.T) {
+	wt := &fakeWorktreeService{path: "/tmp/worktrees/feature"}
+	u := &RemoveInteractor{Worktrees: wt}
+
+	if _, err := u.Execute(RemoveInput{Branch: "feature", DeleteRemote: true}); err == nil {
+		t.Fatal("expected error without upstream")
+	}
+	if wt.removedBranch != "" {
+		t.Fatal("worktree should not be removed")
+	}
+}
+
+type listedWorktreeService struct {
+	fakeWorktreeService
+}
+
+func (f *listedWorktreeService) ListWorktrees() ([]domain.WorktreeInfo, error) {
+	return []domain.WorktreeInfo{{Branch: "refs/heads/feature", Path: "/tmp/worktrees/feature"}}, nil
+}
+
+type fixedProcesses map[string][]domain.Process
+
+func (f fixedProcesses) ProcessesIn(dir string) ([]domain.Process, error) { return f[dir], nil }
+
+func TestRemoveInteractorInspect(t *testing.T) {
+	procs := fixedProcesses{"/tmp/worktrees/feature": {{PID: 7, Command: "npm"}}}
+	u := &RemoveInteractor{Worktrees: &listedWorktreeService{}, Processes: procs}
+
+	r, err := u.Inspect("feature")
+	if err != nil {
+		t.Fatalf("Inspect returned error: %v", err)
+	}
+	if r.Path != "/tmp/worktrees/feature" || !reflect.DeepEqual(r.Processes, procs["/tmp/worktrees/feature"]) {
+		t.Fatalf("unexpected inspection: %+v", r)
+	}
+	if r.Clean() {
+		t.Fatal("running process should make the inspection unclean")
+	}
+
+	if _, err := u.Inspect("missing"); err == nil {
+		t.Fatal("expected error for unknown branch")
+	}
+}
+
+type memoryStateRepo struct{ st domain.State }
+
+func (m *memoryStateRepo) Load() (domain.State, error) { return m.st, nil }
+func (m *memoryStateRepo) Save(st domain.State) error  { m.st = st; return nil }
+
+func TestRemoveInteractorForgetsSessionName(t *testing.T) {
+	repo := &memoryStateRepo{}
+	names := domain.NewSessionNameService(repo, domain.Settings{}, "/tmp")
+	wt := domain.WorktreeInfo{Branch: "feature/foo", Path: "/tmp/worktrees/feature"}
+	if err := names.Remember(wt, "gwm-feature-foo"); err != nil {
+		t.Fatal(err)
+	}
+	u := &RemoveInteractor{Worktrees: &fakeWorktreeService{path: wt.Path}, Launcher: &fakeLauncher{}, Sessions: names}
+
+	if _, err := u.Execute(RemoveInput{Branch: "feature/foo"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(repo.st.Sessions) != 0 {
+		t.Fatalf("session name should be forgotten: %+v", repo.st.Sessions)
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// RenameInput represents the parameters for renaming a worktree's branch.
+type RenameInput struct {
+	Branch    string
+	NewBranch string
+}
+
+// RenameOutput describes the user-facing messages for the rename command.
+type RenameOutput struct {
+	Messages []string
+	// Worktree is the new path of the worktree.
+	Worktree string
+}
+
+// RenameInteractor renames a worktree's branch, moves the worktree to the
+// path of the new name and carries gwm's records (allocation, last use,
+// session name) over to it.
+type RenameInteractor struct {
+	Worktrees domain.WorktreeService
+	Launcher  domain.SessionLauncher
+	Allocator *domain.AllocatorService
+	Usage     *domain.UsageService
+	Sessions  *domain.SessionNameService
+}
+
+// Execute renames the branch and moves its worktree. The main and the
+// current worktree cannot be moved. A running session is kept only when its
+// name is stored; otherwise it would lose its worktree, so Execute refuses.
+func (u *RenameInteractor) Execute(in RenameInput) (RenameOutput, error) {
+	var out RenameOutput
+	from := strings.TrimPrefix(strings.TrimSpace(in.Branch), "refs/heads/")
+	to := strings.TrimPrefix(strings.TrimSpace(in.NewBranch), "refs/heads/")
+	if from == "" || to == "" {
+		return out, fmt.Errorf("branch and new branch names are required")
+	}
+	if from == to {
+		return out, fmt.Errorf("%s already has that name", from)
+	}
+	list, err := u.Worktrees.ListWorktrees()
+	if err != nil {
+		return out, err
+	}
+	wt := findWorktree(list, from)
+	if wt == nil {
+		return out, fmt.Errorf("worktree for branch %s not found", from)
+	}
+	// git worktree list は常にメインの worktree を先頭に返す。
+	if len(list) > 0 && list[0].Path == wt.Path {
+		return out, fmt.Errorf("the main worktree cannot be moved; rename %s with git branch -m", from)
+	}
+	if wt.IsCurrent {
+		return out, fmt.Errorf("leave the worktree of %s before renaming it", from)
+	}
+	if exists, err := u.Worktrees.BranchExists(to); err != nil {
+		return out, err
+	} else if exists {
+		return out, fmt.Errorf("branch %s already exists", to)
+	}
+	if err := u.checkSession(*wt); err != nil {
+		return out, err
+	}
+
+	path, err := u.Worktrees.RenameWorktree(*wt, to)
+	if err != nil {
+		return out, err
+	}
+	out.Worktree = path
+	out.Messages = append(out.Messages, fmt.Sprintf("branch renamed: %s -> %s", from, to), "worktree moved to "+path)
+
+	// ここからは gwm の記録の付け替えだけなので、失敗しても git の変更は戻さない。
+	moved := domain.WorktreeInfo{Branch: "refs/heads/" + to, Path: path}
+	if err := u.Allocator.Rename(from, to, path); err != nil {
+		return out, err
+	}
+	if err := u.Usage.Rename(from, to); err != nil {
+		return out, err
+	}
+	if err := u.Sessions.Move(*wt, moved); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// checkSession refuses a running session that would be lost: launchers find
+// sessions without a stored name by the branch, which is about to change.
+func (u *RenameInteractor) checkSession(wt domain.WorktreeInfo) error {
+	if u.Launcher == nil {
+		return nil
+	}
+	running, err := u.Launcher.HasSession(wt)
+	if err != nil || !running {
+		return err
+	}
+	if _, ok, err := u.Sessions.Lookup(wt); err != nil || ok {
+		return err
+	}
+	return fmt.Errorf("a session is running for %s; kill it before renaming", wt.ShortBranch())
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+type renameWorktreeStub struct {
+	fakeWorktreeService
+	list    []domain.WorktreeInfo
+	exists  bool
+	renamed []string
+}
+
+func (s *renameWorktreeStub) ListWorktrees() ([]domain.WorktreeInfo, error) { return s.list, nil }
+func (s *renameWorktreeStub) BranchExists(string) (bool, error)             { return s.exists, nil }
+func (s *renameWorktreeStub) RenameWorktree(wt domain.WorktreeInfo, newBranch string) (string, error) {
+	s.renamed = append(s.renamed, wt.ShortBranch()+" -> "+newBranch)
+	return "/repo/worktrees/" + newBranch, nil
+}
+
+type sessionLauncherStub struct {
+	mockLauncher
+	running bool
+}
+
+func (s *sessionLauncherStub) HasSession(domain.WorktreeInfo) (bool, error) { return s.running, nil }
+
+func TestRenameInteractorCarriesRecordsOver(t *testing.T) {
+	old := domain.WorktreeInfo{Branch: "refs/heads/feat/old", Path: "/repo/worktrees/feat/old"}
+	used := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	state := &memoryStateRepo{st: domain.State{
+		Allocations: []domain.Allocation{{Branch: "feat/old", Path: old.Path, Index: 2, Port: 3020, PortCount: 10}},
+		LastUsed:    map[string]time.Time{"feat/old": used},
+		Sessions:    []domain.SessionRecord{{Branch: "feat/old", Path: old.Path, Name: "gwm-feat-old"}},
+	}}
+	wt := &renameWorktreeStub{list: []domain.WorktreeInfo{{Branch: "refs/heads/main", Path: "/repo"}, old}}
+	u := &RenameInteractor{
+		Worktrees: wt,
+		// セッションは動いているが名前が記録されているので、名前を付け替えれば残せる。
+		Launcher:  &sessionLauncherStub{running: true},
+		Allocator: domain.NewAllocatorService(state, nil, domain.Settings{}),
+		Usage:     domain.NewUsageService(state),
+		Sessions:  domain.NewSessionNameService(state, domain.Settings{}, "/repo"),
+	}
+
+	out, err := u.Execute(RenameInput{Branch: "feat/old", NewBranch: "feat/new"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	path := "/repo/worktrees/feat/new"
+	if len(wt.renamed) != 1 || wt.renamed[0] != "feat/old -> feat/new" || out.Worktree != path {
+		t.Fatalf("renamed = %v worktree = %q", wt.renamed, out.Worktree)
+	}
+	if a := state.st.Allocations[0]; a.Branch != "feat/new" || a.Path != path || a.Port != 3020 {
+		t.Fatalf("allocation not moved: %+v", a)
+	}
+	if _, ok := state.st.LastUsed["feat/old"]; ok || !state.st.LastUsed["feat/new"].Equal(used) {
+		t.Fatalf("last use not moved: %v", state.st.LastUsed)
+	}
+	if r := state.st.Sessions[0]; r.Branch != "feat/new" || r.Path != path || r.Name != "gwm-feat-old" {
+		t.Fatalf("session record not moved: %+v", r)
+	}
+}
+
+func TestRenameInteractorRefusals(t *testing.T) {
+	main := domain.WorktreeInfo{Branch: "refs/heads/main", Path: "/repo"}
+	feat := domain.WorktreeInfo{Branch: "refs/heads/feat", Path: "/repo/worktrees/feat"}
+	here := domain.WorktreeInfo{Branch: "refs/heads/here", Path: "/repo/worktrees/here", IsCurrent: true}
+	cases := map[string]struct {
+		in      RenameInput
+		exists  bool
+		running bool
+	}{
+		"same name":        {in: RenameInput{Branch: "feat", NewBranch: "feat"}},
+		"unknown branch":   {in: RenameInput{Branch: "nope", NewBranch: "x"}},
+		"main worktree":    {in: RenameInput{Branch: "main", NewBranch: "trunk"}},
+		"current worktree": {in: RenameInput{Branch: "here", NewBranch: "there"}},
+		"existing branch":  {in: RenameInput{Branch: "feat", NewBranch: "main2"}, exists: true},
+		// 名前が記録されていないセッションは新しいブランチ名では見つからなくなる。
+		"unstored session": {in: RenameInput{Branch: "feat", NewBranch: "feat2"}, running: true},
+	}
+	for name, tc := range cases {
+		wt := &renameWorktreeStub{list: []domain.WorktreeInfo{main, feat, here}, exists: tc.exists}
+		u := &RenameInteractor{Worktrees: wt, Launcher: &sessionLauncherStub{running: tc.running}}
+		if _, err := u.Execute(tc.in); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		if len(wt.renamed) != 0 {
+			t.Fatalf("%s: nothing should be renamed, got %v", name, wt.renamed)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// RestoreInput names the archive written by `gwm remove --archive`.
+type RestoreInput struct {
+	Archive string
+}
+
+// RestoreOutput describes the restored worktree.
+type RestoreOutput struct {
+	Messages []string
+	Worktree string
+}
+
+// RestoreInteractor recreates a worktree from an archive and reapplies its
+// uncommitted changes and files.
+type RestoreInteractor struct {
+	Worktrees domain.WorktreeService
+	Archives  domain.ArchiveRepository
+	// Create sets up the branch, worktree, allocation and managed files.
+	// Its Launcher should be nil so that no session replaces the process.
+	Create *CreateInteractor
+}
+
+func (u *RestoreInteractor) Execute(in RestoreInput) (RestoreOutput, error) {
+	var out RestoreOutput
+	snap, err := u.Archives.Load(in.Archive)
+	if err != nil {
+		return out, err
+	}
+	m := snap.Manifest
+	if m.Branch == "" || m.Commit == "" {
+		return out, errors.New("archive manifest has no branch or commit")
+	}
+
+	exists, err := u.Worktrees.BranchExists(m.Branch)
+	if err != nil {
+		return out, err
+	}
+	ci := CreateInput{Branch: m.Branch}
+	threeWay := false
+	if !exists {
+		ci.Base = m.Commit
+	} else {
+		head, err := u.Worktrees.Head(m.Branch)
+		if err != nil {
+			return out, err
+		}
+		if head != m.Commit {
+			threeWay = true
+			out.Messages = append(out.Messages, fmt.Sprintf("branch %s moved since the archive (%s -> %s); changes are applied with a 3-way merge", m.Branch, domain.ShortHash(m.Commit), domain.ShortHash(head)))
+		}
+	}
+
+	res, err := u.Create.Execute(ci)
+	out.Messages = append(out.Messages, res.Messages...)
+	if err != nil {
+		return out, err
+	}
+	out.Worktree = res.Worktree
+
+	// パッチを先に当てる。管理対象ファイルが追跡されていると展開後は apply できない。
+	var patchErr error
+	if len(snap.Patch) > 0 {
+		if patchErr = u.Worktrees.ApplyPatch(res.Worktree, snap.Patch, threeWay); patchErr == nil {
+			out.Messages = append(out.Messages, "uncommitted changes reapplied")
+		}
+	}
+	if err := u.Archives.Extract(in.Archive, res.Worktree); err != nil {
+		return out, err
+	}
+	out.Messages = append(out.Messages, fmt.Sprintf("%d file(s) restored", len(m.Files)))
+	return out, patchErr
+}
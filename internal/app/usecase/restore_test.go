@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+type memoryArchives struct {
+	saved     domain.Snapshot
+	extracted string
+}
+
+func (m *memoryArchives) Save(s domain.Snapshot, worktreePath string) (string, error) {
+	m.saved = s
+	return "/repo/.gwm/archive/feature.tar.gz", nil
+}
+func (m *memoryArchives) Load(string) (domain.Snapshot, error) { return m.saved, nil }
+func (m *memoryArchives) Extract(_, dest string) error {
+	m.extracted = dest
+	return nil
+}
+
+type snapshotWorktrees struct {
+	createWorktreeStub
+	head    string
+	patched []byte
+	three   bool
+}
+
+func (s *snapshotWorktrees) ListWorktrees() ([]domain.WorktreeInfo, error) {
+	return []domain.WorktreeInfo{{Branch: "refs/heads/feature", Path: "/tmp/worktrees/feature"}}, nil
+}
+func (s *snapshotWorktrees) CaptureChanges(string) (domain.ChangeSet, error) {
+	return domain.ChangeSet{Commit: "abc1234567", Patch: []byte("diff"), Untracked: []string{"notes.txt"}}, nil
+}
+func (s *snapshotWorktrees) Head(string) (string, error) { return s.head, nil }
+func (s *snapshotWorktrees) ApplyPatch(_ string, patch []byte, threeWay bool) error {
+	s.patched = patch
+	s.three = threeWay
+	return nil
+}
+
+type envConfigRepo struct{}
+
+func (envConfigRepo) Load() ([]domain.ConfigEntry, error) {
+	return []domain.ConfigEntry{{Path: ".env", Mode: domain.ModeCopy}, {Path: "node_modules", Mode: domain.ModeSymlink}}, nil
+}
+func (envConfigRepo) Save([]domain.ConfigEntry) error { return nil }
+
+func TestRemoveArchiveAndRestore(t *testing.T) {
+	wt := &snapshotWorktrees{}
+	archives := &memoryArchives{}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	rm := &RemoveInteractor{
+		Worktrees: wt, Archives: archives, Config: envConfigRepo{}, FileOps: noopFileOps{},
+		now: func() time.Time { return now },
+	}
+
+	out, err := rm.Execute(RemoveInput{Branch: "feature", Force: true, Archive: true})
+	if err != nil {
+		t.Fatalf("remove returned error: %v", err)
+	}
+	if out.Messages[0] != "archived: /repo/.gwm/archive/feature.tar.gz" {
+		t.Fatalf("unexpected messages: %v", out.Messages)
+	}
+	want := domain.ArchiveManifest{Branch: "feature", Commit: "abc1234567", CreatedAt: now, Files: []string{"notes.txt", ".env"}}
+	if !reflect.DeepEqual(archives.saved.Manifest, want) || string(archives.saved.Patch) != "diff" {
+		t.Fatalf("unexpected snapshot: %+v", archives.saved)
+	}
+
+	// ブランチが残っていて HEAD が動いていない場合は通常の apply。
+	wt.exists, wt.head = true, "abc1234567"
+	u := &RestoreInteractor{
+		Worktrees: wt,
+		Archives:  archives,
+		Create:    &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: noopFileOps{}},
+	}
+	res, err := u.Execute(RestoreInput{Archive: "feature.tar.gz"})
+	if err != nil {
+		t.Fatalf("restore returned error: %v", err)
+	}
+	if res.Worktree != "/tmp/worktrees/feature" || archives.extracted != res.Worktree {
+		t.Fatalf("unexpected restore target: %s (extracted to %s)", res.Worktree, archives.extracted)
+	}
+	if string(wt.patched) != "diff" || wt.three {
+		t.Fatalf("unexpected apply: patch=%q threeWay=%v", wt.patched, wt.three)
+	}
+}
+
+func TestRestoreRecreatesBranchAtCommit(t *testing.T) {
+	wt := &snapshotWorktrees{}
+	archives := &memoryArchives{saved: domain.Snapshot{Manifest: domain.ArchiveManifest{Branch: "feature", Commit: "abc1234567"}}}
+	u := &RestoreInteractor{
+		Worktrees: wt,
+		Archives:  archives,
+		Create:    &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: noopFileOps{}},
+	}
+
+	if _, err := u.Execute(RestoreInput{Archive: "feature.tar.gz"}); err != nil {
+		t.Fatalf("restore returned error: %v", err)
+	}
+	if wt.createdFrom != "abc1234567" {
+		t.Fatalf("branch created from %q", wt.createdFrom)
+	}
+	if wt.patched != nil {
+		t.Fatal("no patch should be applied when the archive has none")
+	}
+}
+
+func TestRestoreThreeWayWhenBranchMoved(t *testing.T) {
+	wt := &snapshotWorktrees{createWorktreeStub: createWorktreeStub{exists: true}, head: "def7654321"}
+	archives := &memoryArchives{saved: domain.Snapshot{
+		Manifest: domain.ArchiveManifest{Branch: "feature", Commit: "abc1234567"},
+		Patch:    []byte("diff"),
+	}}
+	u := &RestoreInteractor{
+		Worktrees: wt,
+		Archives:  archives,
+		Create:    &CreateInteractor{Worktrees: wt, Config: emptyConfigRepo{}, FileOps: noopFileOps{}},
+	}
+
+	out, err := u.Execute(RestoreInput{Archive: "feature.tar.gz"})
+	if err != nil {
+		t.Fatalf("restore returned error: %v", err)
+	}
+	if !wt.three {
+		t.Fatal("expected a 3-way apply")
+	}
+	if out.Messages[0] != "branch feature moved since the archive (abc1234 -> def7654); changes are applied with a 3-way merge" {
+		t.Fatalf("unexpected messages: %v", out.Messages)
+	}
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// ErrNoSession is returned by KillBranch when the worktree has no running
+// session.
+var ErrNoSession = errors.New("no session running")
+
+// SessionInteractor manages the sessions of existing worktrees.
+type SessionInteractor struct {
+	// Worktrees resolves branch names for KillBranch.
+	Worktrees domain.WorktreeService
+	Launcher  domain.SessionLauncher
+	// Lister enumerates sessions for Sessions, Attach and GC; nil when the
+	// launcher has no named sessions.
+	Lister domain.SessionLister
+	// Names holds the stored session names (may be nil).
+	Names *domain.SessionNameService
+	// RepoDir decides which unowned sessions were started for this repository.
+	RepoDir string
+}
+
+// Kill ends the worktree's session; it does nothing when none is running.
+func (u *SessionInteractor) Kill(wt domain.WorktreeInfo) error {
+	if u.Launcher == nil {
+		return errors.New("no session launcher configured")
+	}
+	return u.Launcher.Kill(wt)
+}
+
+// KillBranch ends the session of branch's worktree. Unlike Kill it reports
+// ErrNoSession when nothing was running.
+func (u *SessionInteractor) KillBranch(branch string) error {
+	if u.Launcher == nil {
+		return errors.New("no session launcher configured")
+	}
+	list, err := u.Worktrees.ListWorktrees()
+	if err != nil {
+		return err
+	}
+	wt := findWorktree(list, branch)
+	if wt == nil {
+		return fmt.Errorf("worktree for branch %s not found", branch)
+	}
+	running, err := u.Launcher.HasSession(*wt)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return ErrNoSession
+	}
+	return u.Launcher.Kill(*wt)
+}
+
+// SessionEntry is a gwm session together with the worktree it belongs to.
+type SessionEntry struct {
+	domain.SessionInfo
+	// Worktree is nil when no worktree of this repository owns the session.
+	Worktree *domain.WorktreeInfo
+	// Orphan marks unowned sessions that were started for this repository,
+	// i.e. ones GC kills.
+	Orphan bool
+}
+
+// Sessions lists the running sessions that gwm started: stored names,
+// gwm-prefixed names and legacy unprefixed names named after a worktree.
+// Other sessions of the multiplexer are left out.
+func (u *SessionInteractor) Sessions() ([]SessionEntry, error) {
+	if u.Lister == nil {
+		return nil, errors.New("the configured launcher cannot list sessions")
+	}
+	running, err := u.Lister.Sessions()
+	if err != nil {
+		return nil, err
+	}
+	list, err := u.Worktrees.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	records, err := u.Names.Records()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []SessionEntry
+	for _, s := range running {
+		var rec *domain.SessionRecord
+		for i := range records {
+			if records[i].Name == s.Name {
+				rec = &records[i]
+				break
+			}
+		}
+		inRepo := u.RepoDir != "" && isWithin(u.RepoDir, s.Path)
+		var wt *domain.WorktreeInfo
+		switch {
+		case rec != nil:
+			wt = worktreeAt(list, rec.Path)
+		case strings.HasPrefix(s.Name, "gwm-"):
+			wt = worktreeAt(list, s.Path)
+		case legacySessionName(s.Name, s.Path):
+			// 旧バージョンの接頭辞なしの名前は、今の worktree か記録済みの worktree のパスで
+			// 始まったものだけを扱う。<repo>/docs の docs や隣のプロジェクトのセッションは個人のもの。
+			wt = worktreeAt(list, s.Path)
+			if wt == nil && !recordedPath(records, s.Path) {
+				continue
+			}
+		default:
+			continue
+		}
+		e := SessionEntry{SessionInfo: s, Worktree: wt}
+		if wt == nil {
+			// 別リポジトリの gwm-* はまだ存在するディレクトリで動いているので触らない。
+			e.Orphan = rec != nil || inRepo || !pathExists(s.Path)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Attach attaches to a session given by name or by its worktree's branch.
+func (u *SessionInteractor) Attach(target string) error {
+	if u.Lister == nil {
+		return errors.New("the configured launcher cannot list sessions")
+	}
+	entries, err := u.Sessions()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name == target {
+			return u.Lister.AttachSession(e.Name)
+		}
+	}
+	for _, e := range entries {
+		if e.Worktree != nil && strings.TrimPrefix(e.Worktree.Branch, "refs/heads/") == strings.TrimPrefix(target, "refs/heads/") {
+			return u.Lister.AttachSession(e.Name)
+		}
+	}
+	return fmt.Errorf("session %s not found", target)
+}
+
+// KillOrphan kills an orphaned session listed by Sessions and forgets its
+// stored name. Sessions that still belong to a worktree are refused.
+func (u *SessionInteractor) KillOrphan(e SessionEntry) error {
+	if u.Lister == nil {
+		return errors.New("the configured launcher cannot list sessions")
+	}
+	if !e.Orphan {
+		return fmt.Errorf("session %s is not an orphan", e.Name)
+	}
+	if err := u.Lister.KillSession(e.Name); err != nil {
+		return err
+	}
+	records, err := u.Names.Records()
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if r.Name == e.Name {
+			return u.Names.Forget(domain.WorktreeInfo{Path: r.Path})
+		}
+	}
+	return nil
+}
+
+// legacySessionName reports whether name is an unprefixed session name that
+// older versions derived from the branch or directory of path.
+func legacySessionName(name, path string) bool {
+	if name == "" || path == "" {
+		return false
+	}
+	return strings.HasSuffix("-"+domain.SanitizeSessionName(path), "-"+name)
+}
+
+func recordedPath(records []domain.SessionRecord, path string) bool {
+	for _, r := range records {
+		if path != "" && filepath.Clean(r.Path) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func worktreeAt(list []domain.WorktreeInfo, path string) *domain.WorktreeInfo {
+	if path == "" {
+		return nil
+	}
+	for i := range list {
+		if filepath.Clean(list[i].Path) == filepath.Clean(path) {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func isWithin(dir, path string) bool {
+	if path == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func pathExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
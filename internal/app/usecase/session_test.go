@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+type fakeSessionLister struct {
+	sessions []domain.SessionInfo
+	killed   []string
+}
+
+func (f *fakeSessionLister) Sessions() ([]domain.SessionInfo, error) { return f.sessions, nil }
+func (f *fakeSessionLister) AttachSession(string) error              { return nil }
+func (f *fakeSessionLister) KillSession(name string) error {
+	f.killed = append(f.killed, name)
+	return nil
+}
+
+func TestSessionsClassifiesOwnedOrphanedAndForeign(t *testing.T) {
+	repoDir := t.TempDir()
+	other := t.TempDir()
+	live := filepath.Join(repoDir, "worktrees", "feature", "live")
+	gone := filepath.Join(repoDir, "worktrees", "feature", "gone")
+
+	state := &memoryStateRepo{st: domain.State{Sessions: []domain.SessionRecord{
+		{Branch: "feature/live", Path: live, Name: "gwm-feature-live"},
+		{Branch: "stale", Path: filepath.Join(repoDir, "worktrees", "stale"), Name: "gwm-stale"},
+		// 旧バージョンのセッション feature-gone の worktree も記録に残っている。
+		{Branch: "feature/gone", Path: gone, Name: "gwm-feature-gone"},
+	}}}
+	lister := &fakeSessionLister{sessions: []domain.SessionInfo{
+		{Name: "gwm-feature-live", Path: live},
+		{Name: "gwm-stale", Path: repoDir},
+		{Name: "feature-gone", Path: gone},
+		{Name: "gwm-elsewhere", Path: other},
+		{Name: "work", Path: other},
+	}}
+	u := &SessionInteractor{
+		Worktrees: &pruneWorktreeStub{list: []domain.WorktreeInfo{{Branch: "refs/heads/feature/live", Path: live}}},
+		Lister:    lister,
+		Names:     domain.NewSessionNameService(state, domain.Settings{}, repoDir),
+		RepoDir:   repoDir,
+	}
+
+	entries, err := u.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions returned error: %v", err)
+	}
+	got := map[string]SessionEntry{}
+	for _, e := range entries {
+		got[e.Name] = e
+	}
+	if len(got) != 4 {
+		t.Fatalf("unrelated session should be left out: %+v", entries)
+	}
+	if e := got["gwm-feature-live"]; e.Worktree == nil || e.Orphan {
+		t.Fatalf("live session should map to its worktree: %+v", e)
+	}
+	for _, name := range []string{"gwm-stale", "feature-gone"} {
+		if e := got[name]; e.Worktree != nil || !e.Orphan {
+			t.Fatalf("%s should be an orphan: %+v", name, e)
+		}
+	}
+	if e := got["gwm-elsewhere"]; e.Orphan {
+		t.Fatalf("session of another repository must not be an orphan: %+v", e)
+	}
+
+	if err := u.KillOrphan(got["gwm-stale"]); err != nil {
+		t.Fatalf("KillOrphan returned error: %v", err)
+	}
+	if err := u.KillOrphan(got["gwm-feature-live"]); err == nil {
+		t.Fatal("KillOrphan should refuse a session that has a worktree")
+	}
+	if len(lister.killed) != 1 || lister.killed[0] != "gwm-stale" {
+		t.Fatalf("killed = %v", lister.killed)
+	}
+	if len(state.st.Sessions) != 2 || state.st.Sessions[0].Name != "gwm-feature-live" {
+		t.Fatalf("stored name of the killed session should be forgotten: %+v", state.st.Sessions)
+	}
+}
+
+func TestSessionsIgnoresPersonalSessions(t *testing.T) {
+	parent := t.TempDir()
+	repoDir := filepath.Join(parent, "api")
+	docs := filepath.Join(repoDir, "docs")
+	// worktreePathTemplate が ../{{.Repo}}-{{.BranchSlug}} なら worktree は隣に並ぶ。
+	sibling := filepath.Join(parent, "web")
+	for _, d := range []string{docs, sibling} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lister := &fakeSessionLister{sessions: []domain.SessionInfo{
+		{Name: "docs", Path: docs},
+		{Name: "web", Path: sibling},
+	}}
+	u := &SessionInteractor{
+		Worktrees: &pruneWorktreeStub{list: []domain.WorktreeInfo{
+			{Branch: "refs/heads/main", Path: repoDir},
+			{Branch: "refs/heads/feat", Path: filepath.Join(parent, "api-feat")},
+		}},
+		Lister:  lister,
+		Names:   domain.NewSessionNameService(&memoryStateRepo{}, domain.Settings{}, repoDir),
+		RepoDir: repoDir,
+	}
+
+	// 名前がディレクトリ名と同じでも、worktree のパスでなければ gwm のセッションではない。
+	entries, err := u.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions returned error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("personal sessions should be left out: %+v", entries)
+	}
+	for _, s := range lister.sessions {
+		if err := u.KillOrphan(SessionEntry{SessionInfo: s}); err == nil {
+			t.Fatalf("personal session %s must not be killed", s.Name)
+		}
+	}
+	if len(lister.killed) != 0 {
+		t.Fatalf("killed = %v", lister.killed)
+	}
+}
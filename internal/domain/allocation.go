@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Default port block layout: worktree N gets [PortBase+N*PortBlockSize, +PortBlockSize).
+const (
+	DefaultPortBase      = 3000
+	DefaultPortBlockSize = 10
+	maxPort              = 65535
+)
+
+// Allocation is the index and port block assigned to a worktree.
+type Allocation struct {
+	Branch    string `json:"branch"`
+	Path      string `json:"path"`
+	Index     int    `json:"index"`
+	Port      int    `json:"port"`
+	PortCount int    `json:"portCount"`
+}
+
+// State is the persisted per-worktree registry (.gwm/state.json).
+type State struct {
+	Allocations []Allocation `json:"allocations"`
+	// LastUsed は短いブランチ名ごとの最終利用時刻 (UsageService が更新)。
+	LastUsed map[string]time.Time `json:"lastUsed,omitempty"`
+	// Sessions は worktree ごとに選んだセッション名 (SessionNameService が更新)。
+	Sessions []SessionRecord `json:"sessions,omitempty"`
+}
+
+// StateRepository persists State.
+type StateRepository interface {
+	Load() (State, error)
+	Save(State) error
+}
+
+// PortChecker reports whether a port is already in use on this machine.
+type PortChecker interface {
+	InUse(port int) bool
+}
+
+// AllocatorService assigns each worktree a stable index and port block.
+// Index 0 is reserved for the main checkout. A nil *AllocatorService
+// allocates nothing.
+type AllocatorService struct {
+	repo      StateRepository
+	ports     PortChecker
+	portBase  int
+	blockSize int
+}
+
+func NewAllocatorService(repo StateRepository, ports PortChecker, settings Settings) *AllocatorService {
+	s := &AllocatorService{repo: repo, ports: ports, portBase: settings.PortBase, blockSize: settings.PortBlockSize}
+	if s.portBase <= 0 {
+		s.portBase = DefaultPortBase
+	}
+	if s.blockSize <= 0 {
+		s.blockSize = DefaultPortBlockSize
+	}
+	return s
+}
+
+// Allocate returns the allocation for branch, creating one if needed.
+// The lowest free index whose port block is entirely unused is chosen.
+func (s *AllocatorService) Allocate(branch, path string) (Allocation, error) {
+	if s == nil {
+		return Allocation{}, nil
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	st, err := s.repo.Load()
+	if err != nil {
+		return Allocation{}, err
+	}
+	for _, a := range st.Allocations {
+		if a.Branch == branch {
+			return a, nil
+		}
+	}
+	st.Allocations = pruneStale(st.Allocations)
+
+	used := map[int]bool{0: true}
+	for _, a := range st.Allocations {
+		used[a.Index] = true
+	}
+	for idx := 1; ; idx++ {
+		port := s.portBase + idx*s.blockSize
+		if port+s.blockSize-1 > maxPort {
+			return Allocation{}, errors.New("no free port block left")
+		}
+		if used[idx] || s.blockInUse(port) {
+			continue
+		}
+		a := Allocation{Branch: branch, Path: path, Index: idx, Port: port, PortCount: s.blockSize}
+		st.Allocations = append(st.Allocations, a)
+		return a, s.repo.Save(st)
+	}
+}
+
+// Get returns the allocation for branch if one exists.
+func (s *AllocatorService) Get(branch string) (Allocation, bool, error) {
+	if s == nil {
+		return Allocation{}, false, nil
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	st, err := s.repo.Load()
+	if err != nil {
+		return Allocation{}, false, err
+	}
+	for _, a := range st.Allocations {
+		if a.Branch == branch {
+			return a, true, nil
+		}
+	}
+	return Allocation{}, false, nil
+}
+
+// Release frees the allocation for branch. Missing allocations are ignored.
+func (s *AllocatorService) Release(branch string) error {
+	if s == nil {
+		return nil
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	kept := st.Allocations[:0]
+	for _, a := range st.Allocations {
+		if a.Branch != branch {
+			kept = append(kept, a)
+		}
+	}
+	if len(kept) == len(st.Allocations) {
+		return nil
+	}
+	st.Allocations = kept
+	return s.repo.Save(st)
+}
+
+// Rename moves the allocation of branch to newBranch at path, keeping its
+// index and ports. Missing allocations are ignored.
+func (s *AllocatorService) Rename(branch, newBranch, path string) error {
+	if s == nil {
+		return nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	for i := range st.Allocations {
+		if st.Allocations[i].Branch == branch {
+			st.Allocations[i].Branch = strings.TrimPrefix(newBranch, "refs/heads/")
+			st.Allocations[i].Path = path
+			return s.repo.Save(st)
+		}
+	}
+	return nil
+}
+
+func (s *AllocatorService) blockInUse(port int) bool {
+	if s.ports == nil {
+		return false
+	}
+	for p := port; p < port+s.blockSize; p++ {
+		if s.ports.InUse(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneStale は gwm 外で消された worktree の割り当てを解放する。
+func pruneStale(list []Allocation) []Allocation {
+	kept := list[:0]
+	for _, a := range list {
+		if a.Path != "" {
+			if _, err := os.Stat(a.Path); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+		}
+		kept = append(kept, a)
+	}
+	return kept
+}
+
+// Env returns the allocation as GWM_* environment variables.
+func (a Allocation) Env() []string {
+	if a.Index == 0 {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("GWM_INDEX=%d", a.Index),
+		fmt.Sprintf("GWM_PORT=%d", a.Port),
+		fmt.Sprintf("GWM_PORT_COUNT=%d", a.PortCount),
+	}
+}
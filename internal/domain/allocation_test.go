@@ -0,0 +1,73 @@
+package domain
+
+import "testing"
+
+type memoryState struct{ st State }
+
+func (m *memoryState) Load() (State, error) {
+	return State{
+		Allocations: append([]Allocation{}, m.st.Allocations...),
+		LastUsed:    m.st.LastUsed,
+		Sessions:    append([]SessionRecord{}, m.st.Sessions...),
+	}, nil
+}
+
+func (m *memoryState) Save(st State) error {
+	m.st = st
+	return nil
+}
+
+type busyPorts map[int]bool
+
+func (b busyPorts) InUse(port int) bool { return b[port] }
+
+func TestAllocatorServiceAllocate(t *testing.T) {
+	repo := &memoryState{}
+	// index 1 のブロック (3010-3019) に使用中ポートがある。
+	svc := NewAllocatorService(repo, busyPorts{3015: true}, Settings{})
+
+	a, err := svc.Allocate("refs/heads/feature/foo", "")
+	if err != nil {
+		t.Fatalf("Allocate returned error: %v", err)
+	}
+	if a.Index != 2 || a.Port != 3020 || a.PortCount != 10 || a.Branch != "feature/foo" {
+		t.Fatalf("unexpected allocation: %+v", a)
+	}
+
+	again, err := svc.Allocate("feature/foo", "")
+	if err != nil || again != a {
+		t.Fatalf("allocation should be stable: %+v, %v", again, err)
+	}
+
+	b, err := svc.Allocate("feature/bar", "")
+	if err != nil || b.Index != 3 {
+		t.Fatalf("second allocation = %+v, %v", b, err)
+	}
+
+	if err := svc.Release("feature/foo"); err != nil {
+		t.Fatalf("Release returned error: %v", err)
+	}
+	if _, ok, _ := svc.Get("feature/foo"); ok {
+		t.Fatalf("allocation should be released")
+	}
+	c, err := svc.Allocate("feature/baz", "")
+	if err != nil || c.Index != 2 {
+		t.Fatalf("released index should be reused: %+v, %v", c, err)
+	}
+}
+
+func TestAllocatorServicePrunesStale(t *testing.T) {
+	repo := &memoryState{st: State{Allocations: []Allocation{{Branch: "gone", Path: "/nonexistent/gwm/wt", Index: 1}}}}
+	svc := NewAllocatorService(repo, nil, Settings{PortBase: 4000, PortBlockSize: 5})
+
+	a, err := svc.Allocate("new", "")
+	if err != nil {
+		t.Fatalf("Allocate returned error: %v", err)
+	}
+	if a.Index != 1 || a.Port != 4005 || a.PortCount != 5 {
+		t.Fatalf("stale allocation should be freed: %+v", a)
+	}
+	if len(repo.st.Allocations) != 1 {
+		t.Fatalf("stale entry should be pruned: %+v", repo.st.Allocations)
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// ArchiveVersion is the manifest format written by this version of gwm.
+const ArchiveVersion = 1
+
+// ChangeSet is the uncommitted state of a worktree.
+type ChangeSet struct {
+	// Commit is HEAD of the worktree.
+	Commit string
+	// Patch holds staged and unstaged changes against Commit (binary diff).
+	Patch []byte
+	// Untracked lists worktree-relative files git does not track or ignore.
+	Untracked []string
+}
+
+// ArchiveManifest describes a worktree snapshot taken before removal.
+type ArchiveManifest struct {
+	Version   int       `json:"version"`
+	Branch    string    `json:"branch"`
+	Commit    string    `json:"commit"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Files lists the worktree-relative files stored in the archive.
+	Files []string `json:"files"`
+	// Patch reports whether the archive carries uncommitted changes.
+	Patch bool `json:"patch"`
+}
+
+// Snapshot is the content of an archive apart from the stored files.
+type Snapshot struct {
+	Manifest ArchiveManifest
+	Patch    []byte
+}
+
+// ArchiveRepository stores worktree snapshots as archives.
+type ArchiveRepository interface {
+	// Save writes s and the files in s.Manifest.Files read from worktreePath,
+	// and returns the archive path. Directories are stored recursively and
+	// missing files are skipped; Manifest.Files records what was stored.
+	Save(s Snapshot, worktreePath string) (string, error)
+	// Load reads the manifest and patch of an archive.
+	Load(path string) (Snapshot, error)
+	// Extract writes the stored files under dest.
+	Extract(path, dest string) error
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HookEvent is a lifecycle point at which hooks run.
+type HookEvent string
+
+const (
+	HookPostCreate HookEvent = "post-create"
+	HookPreRemove  HookEvent = "pre-remove"
+	HookPostRemove HookEvent = "post-remove"
+	HookOnEnter    HookEvent = "on-enter"
+)
+
+// HookFailurePolicy decides what happens when a hook fails.
+type HookFailurePolicy string
+
+const (
+	HookAbort HookFailurePolicy = "abort"
+	HookWarn  HookFailurePolicy = "warn"
+)
+
+// DefaultHookTimeout は timeout 未指定のフックに適用する上限。
+const DefaultHookTimeout = 10 * time.Minute
+
+// Hook is a shell command run at a lifecycle point.
+type Hook struct {
+	Command   string            `json:"command"`
+	Timeout   string            `json:"timeout,omitempty"`
+	OnFailure HookFailurePolicy `json:"onFailure,omitempty"`
+}
+
+// HookConfig maps lifecycle points to the hooks run there, in order.
+type HookConfig map[HookEvent][]Hook
+
+// HookContext describes the worktree a hook runs for.
+type HookContext struct {
+	Event        HookEvent
+	Branch       string
+	WorktreePath string
+	RepoRoot     string
+	// Allocation は割り当て済みのポートなど (未割り当てならゼロ値)。
+	Allocation Allocation
+}
+
+// Validate checks the integrity of Hook.
+func (h Hook) Validate() error {
+	if strings.TrimSpace(h.Command) == "" {
+		return errors.New("hook command is required")
+	}
+	if _, err := h.TimeoutDuration(); err != nil {
+		return err
+	}
+	switch h.OnFailure {
+	case "", HookAbort, HookWarn:
+	default:
+		return fmt.Errorf("unsupported onFailure: %s", h.OnFailure)
+	}
+	return nil
+}
+
+// TimeoutDuration parses Timeout, defaulting to DefaultHookTimeout.
+func (h Hook) TimeoutDuration() (time.Duration, error) {
+	if h.Timeout == "" {
+		return DefaultHookTimeout, nil
+	}
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid hook timeout: %s", h.Timeout)
+	}
+	return d, nil
+}
+
+// Validate checks every hook and rejects unknown events.
+func (c HookConfig) Validate() error {
+	for event, hooks := range c {
+		switch event {
+		case HookPostCreate, HookPreRemove, HookPostRemove, HookOnEnter:
+		default:
+			return fmt.Errorf("unknown hook event: %s", event)
+		}
+		for _, h := range hooks {
+			if err := h.Validate(); err != nil {
+				return fmt.Errorf("%s: %w", event, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		h       Hook
+		wantErr bool
+	}{
+		{"ok", Hook{Command: "npm ci"}, false},
+		{"ok full", Hook{Command: "make", Timeout: "30s", OnFailure: HookWarn}, false},
+		{"empty command", Hook{Command: " "}, true},
+		{"bad timeout", Hook{Command: "x", Timeout: "soon"}, true},
+		{"bad policy", Hook{Command: "x", OnFailure: "retry"}, true},
+	}
+	for _, tt := range tests {
+		err := tt.h.Validate()
+		if tt.wantErr != (err != nil) {
+			t.Fatalf("%s: Validate() = %v", tt.name, err)
+		}
+	}
+	if err := (HookConfig{"pre-create": {{Command: "x"}}}).Validate(); err == nil {
+		t.Fatalf("expected error for unknown event")
+	}
+}
+
+type staticHooks HookConfig
+
+func (s staticHooks) LoadHooks() (HookConfig, error) { return HookConfig(s), nil }
+
+type recordingRunner struct {
+	ran  []string
+	ctxs []HookContext
+	fail map[string]bool
+}
+
+func (r *recordingRunner) Run(h Hook, ctx HookContext) error {
+	r.ran = append(r.ran, h.Command)
+	r.ctxs = append(r.ctxs, ctx)
+	if r.fail[h.Command] {
+		return errors.New("exit status 1")
+	}
+	return nil
+}
+
+func TestHookServiceRunPolicies(t *testing.T) {
+	cfg := staticHooks{HookPostCreate: {
+		{Command: "a"},
+		{Command: "b", OnFailure: HookWarn},
+		{Command: "c"},
+		{Command: "d"},
+	}}
+	runner := &recordingRunner{fail: map[string]bool{"b": true, "c": true}}
+	svc := NewHookService(cfg, runner, "/repo")
+
+	msgs, err := svc.Run(HookPostCreate, HookContext{Branch: "foo"})
+	if err == nil {
+		t.Fatalf("expected abort on failing hook c")
+	}
+	if len(runner.ran) != 3 {
+		t.Fatalf("hooks after abort should not run: %v", runner.ran)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %v", msgs)
+	}
+	if runner.ctxs[0].Event != HookPostCreate || runner.ctxs[0].RepoRoot != "/repo" {
+		t.Fatalf("context not filled: %+v", runner.ctxs[0])
+	}
+
+	var nilSvc *HookService
+	if _, err := nilSvc.Run(HookPostCreate, HookContext{}); err != nil {
+		t.Fatalf("nil service should be a no-op: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Process is a running process found inside a worktree.
+type Process struct {
+	PID     int
+	Command string
+}
+
+// ProcessFinder lists processes whose working directory is inside dir.
+type ProcessFinder interface {
+	ProcessesIn(dir string) ([]Process, error)
+}
+
+// RemovalInspection reports what removing a worktree would discard or disturb.
+type RemovalInspection struct {
+	Branch string
+	Path   string
+	// Changed lists tracked files with uncommitted changes.
+	Changed []string
+	// Untracked lists files git does not track (ignored files excluded).
+	Untracked []string
+	// Stashes lists stash entries created on the branch (e.g. "stash@{0}: WIP on ...").
+	Stashes []string
+	// Unpushed counts commits not reachable from any remote-tracking ref.
+	Unpushed  int
+	Processes []Process
+}
+
+// Clean reports whether nothing would be lost or interrupted.
+func (r RemovalInspection) Clean() bool {
+	return len(r.Changed) == 0 && len(r.Untracked) == 0 && len(r.Stashes) == 0 &&
+		r.Unpushed == 0 && len(r.Processes) == 0
+}
+
+// Findings returns one human-readable line per problem, in a fixed order.
+func (r RemovalInspection) Findings() []string {
+	var lines []string
+	if n := len(r.Changed); n > 0 {
+		lines = append(lines, fmt.Sprintf("%d uncommitted change(s): %s", n, summarize(r.Changed)))
+	}
+	if n := len(r.Untracked); n > 0 {
+		lines = append(lines, fmt.Sprintf("%d untracked file(s): %s", n, summarize(r.Untracked)))
+	}
+	for _, s := range r.Stashes {
+		lines = append(lines, "stash: "+s)
+	}
+	if r.Unpushed > 0 {
+		lines = append(lines, fmt.Sprintf("%d unpushed commit(s)", r.Unpushed))
+	}
+	for _, p := range r.Processes {
+		lines = append(lines, fmt.Sprintf("process running inside: %s (pid %d)", p.Command, p.PID))
+	}
+	return lines
+}
+
+// summarize joins the first few names so long lists stay on one line.
+func summarize(names []string) string {
+	const max = 3
+	if len(names) <= max {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s, ... (+%d)", strings.Join(names[:max], ", "), len(names)-max)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemovalInspectionFindings(t *testing.T) {
+	var r RemovalInspection
+	if !r.Clean() || len(r.Findings()) != 0 {
+		t.Fatalf("empty inspection should be clean: %v", r.Findings())
+	}
+
+	r = RemovalInspection{
+		Changed:   []string{"a.go"},
+		Untracked: []string{"1", "2", "3", "4", "5"},
+		Stashes:   []string{"stash@{0}: WIP on feature: abc fix"},
+		Unpushed:  2,
+		Processes: []Process{{PID: 42, Command: "vim"}},
+	}
+	want := []string{
+		"1 uncommitted change(s): a.go",
+		"5 untracked file(s): 1, 2, 3, ... (+2)",
+		"stash: stash@{0}: WIP on feature: abc fix",
+		"2 unpushed commit(s)",
+		"process running inside: vim (pid 42)",
+	}
+	if r.Clean() {
+		t.Fatal("inspection with findings reported clean")
+	}
+	if got := r.Findings(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Findings() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Split directions accepted by LayoutPane.Split.
+const (
+	// SplitHorizontal places the new pane to the right (tmux split-window -h).
+	SplitHorizontal = "horizontal"
+	// SplitVertical places the new pane below (tmux split-window -v).
+	SplitVertical = "vertical"
+)
+
+// SessionLayout は新しく作るセッションのウィンドウ構成。
+type SessionLayout struct {
+	Windows []LayoutWindow `json:"windows"`
+}
+
+// LayoutWindow is one window. Its first pane runs Command in Dir; Panes are
+// split off it in order.
+type LayoutWindow struct {
+	Name string `json:"name,omitempty"`
+	// Dir は worktree からの相対パス。空なら worktree 直下。
+	Dir     string       `json:"dir,omitempty"`
+	Command string       `json:"command,omitempty"`
+	Panes   []LayoutPane `json:"panes,omitempty"`
+	// Layout は分割後に適用する tmux のレイアウト名 (例: "main-vertical", "tiled")。
+	Layout string `json:"layout,omitempty"`
+}
+
+// LayoutPane is a pane split off the window's current pane.
+type LayoutPane struct {
+	// Split は "horizontal" (右に分割、デフォルト) または "vertical" (下に分割)。
+	Split string `json:"split,omitempty"`
+	// Size は新しいペインの大きさ (%)。0 なら半分。
+	Size int `json:"size,omitempty"`
+	// Dir は worktree からの相対パス。空ならウィンドウの Dir。
+	Dir     string `json:"dir,omitempty"`
+	Command string `json:"command,omitempty"`
+}
+
+// LayoutOverride replaces the default layout for branches matching Branch
+// (a glob such as "feature/*"; "**" matches several segments).
+type LayoutOverride struct {
+	Branch string        `json:"branch"`
+	Layout SessionLayout `json:"layout"`
+}
+
+// Validate checks directions, sizes and that every directory stays inside
+// the worktree.
+func (l SessionLayout) Validate() error {
+	for i, w := range l.Windows {
+		name := w.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if err := validateLayoutDir(w.Dir); err != nil {
+			return fmt.Errorf("window %s: %w", name, err)
+		}
+		for j, p := range w.Panes {
+			switch p.Split {
+			case "", SplitHorizontal, SplitVertical:
+			default:
+				return fmt.Errorf("window %s pane %d: unsupported split: %s", name, j+1, p.Split)
+			}
+			if p.Size < 0 || p.Size >= 100 {
+				return fmt.Errorf("window %s pane %d: size must be between 1 and 99", name, j+1)
+			}
+			if err := validateLayoutDir(p.Dir); err != nil {
+				return fmt.Errorf("window %s pane %d: %w", name, j+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateLayoutDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	clean := filepath.ToSlash(filepath.Clean(dir))
+	if filepath.IsAbs(dir) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("dir must be inside the worktree: %s", dir)
+	}
+	return nil
+}
+
+// ValidateLayouts checks the default layout and every override.
+func (s Settings) ValidateLayouts() error {
+	if s.Layout != nil {
+		if err := s.Layout.Validate(); err != nil {
+			return fmt.Errorf("layout: %w", err)
+		}
+	}
+	for _, o := range s.LayoutOverrides {
+		if strings.TrimSpace(o.Branch) == "" {
+			return fmt.Errorf("layoutOverrides: branch pattern is required")
+		}
+		if err := validatePattern(o.Branch); err != nil {
+			return fmt.Errorf("layoutOverrides %s: %w", o.Branch, err)
+		}
+		if err := o.Layout.Validate(); err != nil {
+			return fmt.Errorf("layoutOverrides %s: %w", o.Branch, err)
+		}
+	}
+	return nil
+}
+
+// LayoutFor returns the layout for branch: the first matching override, else
+// the default layout. ok is false when neither applies.
+func (s Settings) LayoutFor(branch string) (layout SessionLayout, ok bool) {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	for _, o := range s.LayoutOverrides {
+		if matched, _ := MatchPattern(o.Branch, branch); matched {
+			return o.Layout, true
+		}
+	}
+	if s.Layout != nil {
+		return *s.Layout, true
+	}
+	return SessionLayout{}, false
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestSessionLayoutValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		layout  SessionLayout
+		wantErr bool
+	}{
+		{"empty", SessionLayout{}, false},
+		{"panes", SessionLayout{Windows: []LayoutWindow{{Name: "dev", Dir: "web", Panes: []LayoutPane{{Split: SplitVertical, Size: 30}, {}}}}}, false},
+		{"bad split", SessionLayout{Windows: []LayoutWindow{{Panes: []LayoutPane{{Split: "diagonal"}}}}}, true},
+		{"bad size", SessionLayout{Windows: []LayoutWindow{{Panes: []LayoutPane{{Size: 100}}}}}, true},
+		{"escaping dir", SessionLayout{Windows: []LayoutWindow{{Dir: "../other"}}}, true},
+		{"absolute pane dir", SessionLayout{Windows: []LayoutWindow{{Panes: []LayoutPane{{Dir: "/tmp"}}}}}, true},
+	}
+	for _, c := range cases {
+		err := c.layout.Validate()
+		if (err != nil) != c.wantErr {
+			t.Fatalf("%s: Validate() = %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestSettingsLayoutFor(t *testing.T) {
+	def := SessionLayout{Windows: []LayoutWindow{{Name: "default"}}}
+	s := Settings{
+		Layout: &def,
+		LayoutOverrides: []LayoutOverride{
+			{Branch: "docs/*", Layout: SessionLayout{Windows: []LayoutWindow{{Name: "docs"}}}},
+			{Branch: "feature/**", Layout: SessionLayout{Windows: []LayoutWindow{{Name: "feature"}}}},
+		},
+	}
+	cases := map[string]string{
+		"refs/heads/docs/readme": "docs",
+		"feature/api/v2":         "feature",
+		"main":                   "default",
+	}
+	for branch, want := range cases {
+		got, ok := s.LayoutFor(branch)
+		if !ok || got.Windows[0].Name != want {
+			t.Fatalf("LayoutFor(%q) = %+v, %v; want %s", branch, got, ok, want)
+		}
+	}
+
+	if _, ok := (Settings{}).LayoutFor("main"); ok {
+		t.Fatal("no layout configured should report ok=false")
+	}
+	if err := (Settings{LayoutOverrides: []LayoutOverride{{Branch: "[", Layout: def}}}).ValidateLayouts(); err == nil {
+		t.Fatal("invalid branch pattern should be rejected")
+	}
+}
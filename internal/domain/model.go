@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Mode indicates how a file should be deployed into a worktree.
@@ -13,6 +14,8 @@ type Mode string
 const (
 	ModeCopy    Mode = "copy"
 	ModeSymlink Mode = "symlink"
+	// ModeTemplate renders the file with text/template (see TemplateVars).
+	ModeTemplate Mode = "template"
 )
 
 // EntryType describes whether a config target is a file or directory.
@@ -21,13 +24,18 @@ type EntryType string
 const (
 	EntryTypeFile EntryType = "file"
 	EntryTypeDir  EntryType = "dir"
+	// EntryTypeGlob marks a pattern entry resolved at deploy time.
+	EntryTypeGlob EntryType = "glob"
 )
 
 // ConfigEntry represents a file managed by gwm.
+// Path may be a glob pattern ("**/.env.local"); Exclude then lists patterns
+// whose matches are skipped.
 type ConfigEntry struct {
-	Path string    `json:"path"`
-	Mode Mode      `json:"mode"`
-	Type EntryType `json:"type,omitempty"`
+	Path    string    `json:"path"`
+	Mode    Mode      `json:"mode"`
+	Type    EntryType `json:"type,omitempty"`
+	Exclude []string  `json:"exclude,omitempty"`
 }
 
 // Validate checks the integrity of ConfigEntry.
@@ -40,22 +48,110 @@ func (c ConfigEntry) Validate() error {
 	}
 	switch c.Mode {
 	case ModeCopy, ModeSymlink:
+	case ModeTemplate:
+		if c.Type == EntryTypeDir {
+			return errors.New("template mode requires a file")
+		}
 	default:
 		return fmt.Errorf("unsupported mode: %s", c.Mode)
 	}
 	switch c.Type {
 	case EntryTypeFile, EntryTypeDir:
+		if len(c.Exclude) > 0 {
+			return errors.New("exclude is only allowed for glob patterns")
+		}
+	case EntryTypeGlob:
+		if !IsPattern(c.Path) {
+			return fmt.Errorf("glob entry has no pattern: %s", c.Path)
+		}
+		for _, p := range append([]string{c.Path}, c.Exclude...) {
+			if err := validatePattern(p); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+		}
 	default:
 		return fmt.Errorf("unsupported type: %s", c.Type)
 	}
 	return nil
 }
 
+// Excluded reports whether rel matches one of the Exclude patterns.
+func (c ConfigEntry) Excluded(rel string) bool {
+	for _, p := range c.Exclude {
+		if ok, _ := MatchPattern(p, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateVars are the variables available to files deployed in template mode.
+type TemplateVars struct {
+	Repo         string // base name of the main repository directory
+	RepoDir      string
+	Branch       string
+	BranchSlug   string
+	WorktreePath string
+	// Index / Port / PortCount は AllocatorService が割り当てた番号とポート範囲。
+	Index     int
+	Port      int
+	PortCount int
+}
+
 // WorktreeInfo describes a git worktree.
+// ListWorktrees fills the fields up to PrunableReason from
+// `git worktree list --porcelain`; the remaining fields are filled on demand
+// (WorktreeService.Describe, SessionLauncher.HasSession, UsageService).
+// Branch is empty for bare and detached worktrees.
 type WorktreeInfo struct {
-	Branch    string `json:"branch"`
-	Path      string `json:"path"`
-	IsCurrent bool   `json:"isCurrent"`
+	Branch         string `json:"branch"`
+	Path           string `json:"path"`
+	IsCurrent      bool   `json:"isCurrent"`
+	Head           string `json:"head,omitempty"`
+	Bare           bool   `json:"bare,omitempty"`
+	Detached       bool   `json:"detached,omitempty"`
+	Locked         bool   `json:"locked,omitempty"`
+	LockReason     string `json:"lockReason,omitempty"`
+	Prunable       bool   `json:"prunable,omitempty"`
+	PrunableReason string `json:"prunableReason,omitempty"`
+
+	Dirty      bool      `json:"dirty"`
+	Upstream   string    `json:"upstream,omitempty"`
+	Ahead      int       `json:"ahead"`
+	Behind     int       `json:"behind"`
+	HasSession bool      `json:"hasSession"`
+	LastUsed   time.Time `json:"lastUsed,omitzero"`
+}
+
+// ShortBranch returns Branch without the refs/heads/ prefix.
+func (w WorktreeInfo) ShortBranch() string {
+	return strings.TrimPrefix(w.Branch, "refs/heads/")
+}
+
+// Flags lists the special states (bare, detached, locked, prunable) of w.
+func (w WorktreeInfo) Flags() []string {
+	var flags []string
+	if w.Bare {
+		flags = append(flags, "bare")
+	}
+	if w.Detached {
+		flags = append(flags, "detached")
+	}
+	if w.Locked {
+		flags = append(flags, "locked")
+	}
+	if w.Prunable {
+		flags = append(flags, "prunable")
+	}
+	return flags
+}
+
+// BranchStatus describes a local branch for pruning decisions.
+type BranchStatus struct {
+	Merged       bool      // merged into the requested ref
+	Default      bool      // the repository's default branch
+	UpstreamGone bool      // upstream configured but deleted on the remote
+	LastCommit   time.Time // committer date of the branch tip
 }
 
 // CommandResult holds user-facing messages and errors.
@@ -18,6 +18,12 @@ func TestConfigEntryValidate(t *testing.T) {
 		{"abs path", ConfigEntry{Path: "/abs", Mode: ModeCopy, Type: EntryTypeFile}, true},
 		{"bad mode", ConfigEntry{Path: "x", Mode: Mode("bad"), Type: EntryTypeFile}, true},
 		{"bad type", ConfigEntry{Path: "x", Mode: ModeCopy, Type: EntryType("bad")}, true},
+		{"ok glob", ConfigEntry{Path: "**/.env.local", Mode: ModeCopy, Type: EntryTypeGlob, Exclude: []string{"legacy/**"}}, false},
+		{"glob without pattern", ConfigEntry{Path: ".env", Mode: ModeCopy, Type: EntryTypeGlob}, true},
+		{"bad glob", ConfigEntry{Path: "[a", Mode: ModeCopy, Type: EntryTypeGlob}, true},
+		{"ok template", ConfigEntry{Path: ".env", Mode: ModeTemplate, Type: EntryTypeFile}, false},
+		{"template dir", ConfigEntry{Path: "dir", Mode: ModeTemplate, Type: EntryTypeDir}, true},
+		{"exclude on file", ConfigEntry{Path: "x", Mode: ModeCopy, Type: EntryTypeFile, Exclude: []string{"y"}}, true},
 	}
 	for _, tt := range tests {
 		err := tt.e.Validate()
@@ -44,6 +50,15 @@ func TestConfigServiceAddAndRemove(t *testing.T) {
 	if err := svc.Add(ConfigEntry{Path: "a.txt", Mode: ModeCopy}); err == nil {
 		t.Fatalf("expected duplicate error")
 	}
+	if err := svc.Add(ConfigEntry{Path: "**/.env.*", Mode: ModeCopy}); err != nil {
+		t.Fatalf("add pattern err: %v", err)
+	}
+	if repo.data[1].Type != EntryTypeGlob {
+		t.Fatalf("pattern type = %s, want glob", repo.data[1].Type)
+	}
+	if err := svc.Remove("**/.env.*"); err != nil {
+		t.Fatalf("remove pattern err: %v", err)
+	}
 	if err := svc.Remove("missing"); err == nil {
 		t.Fatalf("expected missing error")
 	}
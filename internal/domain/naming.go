@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultWorktreePathTemplate は従来どおりリポジトリ直下の worktrees/<branch> に置く。
+const DefaultWorktreePathTemplate = "worktrees/{{.Branch}}"
+
+// WorktreePathVars are the variables available in Settings.WorktreePathTemplate.
+type WorktreePathVars struct {
+	Repo       string // base name of the main repository directory
+	RepoDir    string // absolute path of the main repository
+	Branch     string // branch name as given (may contain "/")
+	BranchSlug string // branch name flattened into a single path segment
+}
+
+// BranchSlug converts a branch name into a single safe path segment,
+// e.g. "feature/foo bar" -> "feature-foo-bar".
+func BranchSlug(branch string) string {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	var b strings.Builder
+	dash := false
+	for _, r := range branch {
+		ok := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			r == '.' || r == '_' || r == '-'
+		if !ok {
+			r = '-'
+		}
+		if r == '-' {
+			if dash {
+				continue
+			}
+			dash = true
+		} else {
+			dash = false
+		}
+		b.WriteRune(r)
+	}
+	return strings.Trim(b.String(), "-.")
+}
+
+// SanitizeSessionName keeps the characters every terminal multiplexer
+// accepts in a session name: "/" becomes "-" and other punctuation is dropped.
+func SanitizeSessionName(name string) string {
+	s := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') {
+			return r
+		}
+		if r == '-' || r == '_' {
+			return r
+		}
+		if r == '/' || r == '\\' {
+			return '-'
+		}
+		return -1
+	}, name)
+	return strings.Trim(s, "-_")
+}
+
+// SessionName is the session gwm uses for a worktree: "gwm-" plus the
+// sanitized branch, or the directory name for a detached worktree. It is
+// empty when neither yields a usable name.
+func SessionName(wt WorktreeInfo) string {
+	for _, n := range []string{strings.TrimPrefix(wt.Branch, "refs/heads/"), wt.Branch, filepath.Base(wt.Path)} {
+		if s := SanitizeSessionName(n); s != "" {
+			return "gwm-" + s
+		}
+	}
+	return ""
+}
+
+// RenderWorktreePath expands tmpl for branch and returns an absolute, cleaned path.
+// Relative results are resolved against repoDir and "~/" against the home directory.
+func RenderWorktreePath(tmpl, repoDir, branch string) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultWorktreePathTemplate
+	}
+	t, err := template.New("worktreePath").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid worktreePathTemplate: %w", err)
+	}
+	vars := WorktreePathVars{
+		Repo:       filepath.Base(repoDir),
+		RepoDir:    repoDir,
+		Branch:     strings.TrimPrefix(branch, "refs/heads/"),
+		BranchSlug: BranchSlug(branch),
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("invalid worktreePathTemplate: %w", err)
+	}
+	path := strings.TrimSpace(buf.String())
+	if path == "" {
+		return "", errors.New("worktreePathTemplate rendered an empty path")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoDir, path)
+	}
+	path = filepath.Clean(path)
+	if path == filepath.Clean(repoDir) {
+		return "", errors.New("worktreePathTemplate must not resolve to the repository root")
+	}
+	return path, nil
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBranchSlug(t *testing.T) {
+	cases := map[string]string{
+		"feature/foo":         "feature-foo",
+		"refs/heads/feat/a.b": "feat-a.b",
+		"fix//weird name":     "fix-weird-name",
+		"-lead/":              "lead",
+	}
+	for in, want := range cases {
+		if got := BranchSlug(in); got != want {
+			t.Fatalf("BranchSlug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderWorktreePath(t *testing.T) {
+	repo := "/src/app"
+	home, _ := os.UserHomeDir()
+	tests := []struct {
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{"", "/src/app/worktrees/feature/foo", false},
+		{"../{{.Repo}}-worktrees/{{.BranchSlug}}", "/src/app-worktrees/feature-foo", false},
+		{"~/wt/{{.Repo}}/{{.BranchSlug}}", filepath.Join(home, "wt/app/feature-foo"), false},
+		{"/abs/{{.Branch}}", "/abs/feature/foo", false},
+		{"{{.Unknown}}", "", true},
+		{".", "", true},
+	}
+	for _, tt := range tests {
+		got, err := RenderWorktreePath(tt.tmpl, repo, "refs/heads/feature/foo")
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%q: expected error, got %q", tt.tmpl, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("%q: got (%q, %v), want %q", tt.tmpl, got, err, tt.want)
+		}
+	}
+}
+
+func TestSanitizeSessionName(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"feature/foo", "feature-foo"},
+		{"bugfix_123", "bugfix_123"},
+		{"../weird", "weird"},
+		{"ABC:def", "ABCdef"},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		if got := SanitizeSessionName(c.in); got != c.want {
+			t.Fatalf("SanitizeSessionName(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestSessionName(t *testing.T) {
+	cases := []struct {
+		wt   WorktreeInfo
+		want string
+	}{
+		{WorktreeInfo{Branch: "refs/heads/feature/foo", Path: "/wt/feature-foo"}, "gwm-feature-foo"},
+		{WorktreeInfo{Path: "/wt/detached"}, "gwm-detached"},
+		{WorktreeInfo{Branch: "...", Path: "/"}, ""},
+	}
+	for _, c := range cases {
+		if got := SessionName(c.wt); got != c.want {
+			t.Fatalf("SessionName(%+v) = %q, want %q", c.wt, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsPattern reports whether p contains glob metacharacters.
+func IsPattern(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// MatchPattern reports whether rel (a repository-relative path) matches pattern.
+// "**" matches zero or more path segments; other segments follow path.Match.
+func MatchPattern(pattern, rel string) (bool, error) {
+	return matchSegments(splitPath(pattern), splitPath(rel))
+}
+
+// PatternRoot returns the leading literal directories of pattern,
+// i.e. where a walk for matches can start ("" for the repository root).
+func PatternRoot(pattern string) string {
+	var root []string
+	segs := splitPath(pattern)
+	for _, seg := range segs[:max(len(segs)-1, 0)] {
+		if IsPattern(seg) {
+			break
+		}
+		root = append(root, seg)
+	}
+	return strings.Join(root, "/")
+}
+
+func validatePattern(pattern string) error {
+	for _, seg := range splitPath(pattern) {
+		if seg == "**" {
+			continue
+		}
+		if _, err := path.Match(seg, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(filepath.ToSlash(filepath.Clean(p)), "/")
+	if p == "" || p == "." {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func matchSegments(pat, segs []string) (bool, error) {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				ok, err := matchSegments(pat[1:], segs[i:])
+				if err != nil || ok {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(segs) == 0 {
+			return false, nil
+		}
+		ok, err := path.Match(pat[0], segs[0])
+		if err != nil || !ok {
+			return false, err
+		}
+		pat, segs = pat[1:], segs[1:]
+	}
+	return len(segs) == 0, nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"**/.env.local", ".env.local", true},
+		{"**/.env.local", "apps/web/.env.local", true},
+		{"**/.env.local", "apps/web/.env", false},
+		{".env.*", ".env.test", true},
+		{".env.*", "apps/.env.test", false},
+		{"packages/*/node_modules/.cache", "packages/ui/node_modules/.cache", true},
+		{"packages/**/node_modules/.cache", "packages/a/b/node_modules/.cache", true},
+		{"legacy/**", "legacy/x/.env.local", true},
+	}
+	for _, tt := range tests {
+		got, err := MatchPattern(tt.pattern, tt.rel)
+		if err != nil || got != tt.want {
+			t.Fatalf("MatchPattern(%q, %q) = (%v, %v), want %v", tt.pattern, tt.rel, got, err, tt.want)
+		}
+	}
+}
+
+func TestPatternRoot(t *testing.T) {
+	cases := map[string]string{
+		"**/.env.local":                  "",
+		"packages/*/node_modules/.cache": "packages",
+		"apps/web/config/*.json":         "apps/web/config",
+		".env.*":                         "",
+	}
+	for in, want := range cases {
+		if got := PatternRoot(in); got != want {
+			t.Fatalf("PatternRoot(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Commit summarizes a single commit.
+type Commit struct {
+	Hash    string
+	Subject string
+	Author  string
+	Date    time.Time
+}
+
+// ShortHash is the abbreviated hash shown to users.
+func (c Commit) ShortHash() string { return ShortHash(c.Hash) }
+
+// ShortHash abbreviates a commit hash to 7 characters.
+func ShortHash(h string) string {
+	if len(h) > 7 {
+		return h[:7]
+	}
+	return h
+}
+
+// WorktreePreview holds the details shown for the highlighted worktree in
+// the picker.
+type WorktreePreview struct {
+	// Worktree has the Describe fields (upstream, ahead/behind) filled.
+	Worktree   WorktreeInfo
+	LastCommit Commit
+	// Status holds `git status --short` lines.
+	Status     []string
+	HasSession bool
+	Windows    int
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // ConfigRepository persists config entries.
@@ -16,21 +17,159 @@ type ConfigRepository interface {
 // WorktreeService abstracts git worktree operations.
 type WorktreeService interface {
 	BranchExists(branch string) (bool, error)
-	CreateBranch(branch string) error
+	// CreateBranch creates branch from base (the default branch when empty)
+	// and returns the base ref actually used. track sets base as upstream.
+	CreateBranch(branch, base string, track bool) (string, error)
+	// DeleteBranch deletes a local branch (git branch -d, or -D when force).
+	DeleteBranch(branch string, force bool) error
+	// Upstream returns the remote and remote branch name branch tracks.
+	// ok is false when no upstream is configured.
+	Upstream(branch string) (remote, name string, ok bool, err error)
+	// UnpushedCommits counts commits of branch not reachable from HEAD or any
+	// remote-tracking ref, ignoring the given refs (e.g. "origin/feature").
+	UnpushedCommits(branch string, ignore ...string) (int, error)
+	// DeleteRemoteBranch deletes name on remote (git push --delete).
+	DeleteRemoteBranch(remote, name string) error
+	// Inspect reports uncommitted changes, untracked files, stashes and
+	// unpushed commits that removing wt would put at risk. Processes are not filled.
+	Inspect(wt WorktreeInfo) (RemovalInspection, error)
+	// CaptureChanges returns HEAD, the uncommitted diff and the untracked
+	// files of the worktree at path.
+	CaptureChanges(path string) (ChangeSet, error)
+	// ApplyPatch applies a patch from CaptureChanges to the worktree at path.
+	// threeWay falls back to a 3-way merge when HEAD has moved.
+	ApplyPatch(path string, patch []byte, threeWay bool) error
+	// Head returns the commit branch points to.
+	Head(branch string) (string, error)
+	// LastCommit returns HEAD of the worktree at path.
+	LastCommit(path string) (Commit, error)
+	// ShortStatus returns `git status --short` lines of the worktree at path.
+	ShortStatus(path string) ([]string, error)
 	AddWorktree(branch string) (string, error)
 	ListWorktrees() ([]WorktreeInfo, error)
+	// Describe fills the working tree state (dirty, upstream, ahead/behind) of wt.
+	Describe(wt WorktreeInfo) (WorktreeInfo, error)
 	RemoveWorktree(branch string, force bool) (string, error)
+	// RenameWorktree renames the branch of wt (git branch -m) and moves the
+	// worktree to the path newBranch renders to, which it returns.
+	RenameWorktree(wt WorktreeInfo, newBranch string) (string, error)
+	// BranchStatuses reports merge/upstream/commit state of local branches,
+	// keyed by short branch name. mergedInto defaults to the default branch.
+	BranchStatuses(mergedInto string) (map[string]BranchStatus, error)
+	// PruneWorktrees drops admin entries of worktrees whose directory is gone.
+	PruneWorktrees() error
 }
 
 // FileOperator deploys files into a worktree.
 type FileOperator interface {
-	Deploy(entries []ConfigEntry, worktreePath string) error
+	Deploy(entries []ConfigEntry, worktreePath string, vars TemplateVars) error
+	// Expand resolves entry to the repository-relative paths it currently covers.
+	Expand(entry ConfigEntry) ([]string, error)
 }
 
+// LaunchTarget selects where SessionLauncher.Launch opens a worktree.
+type LaunchTarget string
+
+const (
+	// TargetSession opens (or reuses) the worktree's own session.
+	TargetSession LaunchTarget = ""
+	// TargetWindow opens the worktree as a window of the current session.
+	TargetWindow LaunchTarget = "window"
+	// TargetPane splits the current window.
+	TargetPane LaunchTarget = "pane"
+)
+
+// LaunchOptions tune SessionLauncher.Launch; the zero value opens the
+// worktree's own session. Launchers without windows ignore Target.
+type LaunchOptions struct {
+	Target LaunchTarget
+	// Detach creates the session (or window/pane) in the background without
+	// attaching to it. Launchers without background sessions return
+	// ErrDetachUnsupported.
+	Detach bool
+}
+
+// ErrDetachUnsupported is returned by launchers that have no session that
+// could keep running in the background.
+var ErrDetachUnsupported = errors.New("the configured launcher cannot start a session in the background")
+
 // SessionLauncher launches or attaches to a session (tmuxなど) rooted at the worktree.
 type SessionLauncher interface {
-	Launch(worktree WorktreeInfo) error
+	Launch(worktree WorktreeInfo, opts LaunchOptions) error
 	Kill(worktree WorktreeInfo) error
+	// HasSession reports whether a session for the worktree is running.
+	HasSession(worktree WorktreeInfo) (bool, error)
+	// Windows returns the number of windows in the worktree's session
+	// (0 when there is none or the launcher has no windows).
+	Windows(worktree WorktreeInfo) (int, error)
+}
+
+// SessionInfo describes a running multiplexer session.
+type SessionInfo struct {
+	Name string `json:"name"`
+	// Path はセッションの開始ディレクトリ。
+	Path     string    `json:"path"`
+	Attached bool      `json:"attached"`
+	Windows  int       `json:"windows"`
+	Created  time.Time `json:"created"`
+}
+
+// SessionLister enumerates and controls sessions by name. Only launchers
+// with named sessions (tmux) implement it.
+type SessionLister interface {
+	Sessions() ([]SessionInfo, error)
+	KillSession(name string) error
+	AttachSession(name string) error
+}
+
+// HookRepository loads lifecycle hook definitions.
+type HookRepository interface {
+	LoadHooks() (HookConfig, error)
+}
+
+// HookRunner executes a single hook.
+type HookRunner interface {
+	Run(hook Hook, ctx HookContext) error
+}
+
+// HookService runs the hooks registered for a lifecycle point.
+// A nil *HookService runs nothing.
+type HookService struct {
+	repo    HookRepository
+	runner  HookRunner
+	repoDir string
+}
+
+func NewHookService(repo HookRepository, runner HookRunner, repoDir string) *HookService {
+	return &HookService{repo: repo, runner: runner, repoDir: repoDir}
+}
+
+// Run executes hooks for event in order. Failures of "warn" hooks are
+// reported as messages; any other failure stops and is returned.
+func (s *HookService) Run(event HookEvent, ctx HookContext) ([]string, error) {
+	if s == nil {
+		return nil, nil
+	}
+	cfg, err := s.repo.LoadHooks()
+	if err != nil {
+		return nil, err
+	}
+	ctx.Event = event
+	if ctx.RepoRoot == "" {
+		ctx.RepoRoot = s.repoDir
+	}
+	var msgs []string
+	for _, h := range cfg[event] {
+		if err := s.runner.Run(h, ctx); err != nil {
+			if h.OnFailure == HookWarn {
+				msgs = append(msgs, fmt.Sprintf("%s hook failed (ignored): %s: %v", event, h.Command, err))
+				continue
+			}
+			return msgs, fmt.Errorf("%s hook failed: %s: %w", event, h.Command, err)
+		}
+		msgs = append(msgs, fmt.Sprintf("%s hook ran: %s", event, h.Command))
+	}
+	return msgs, nil
 }
 
 // ConfigService offers add/list/remove operations on config entries.
@@ -100,6 +239,16 @@ func (s *ConfigService) Remove(path string) error {
 }
 
 func (s *ConfigService) assignType(entry *ConfigEntry) error {
+	// パターンは展開時に解決するので、登録時には存在を確認しない。
+	if IsPattern(entry.Path) {
+		if entry.Type == "" {
+			entry.Type = EntryTypeGlob
+		}
+		if entry.Type != EntryTypeGlob {
+			return fmt.Errorf("type mismatch: %s is a pattern", entry.Path)
+		}
+		return nil
+	}
 	info, err := os.Stat(filepath.Join(s.repoDir, entry.Path))
 	if err != nil {
 		return err
@@ -0,0 +1,243 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultSessionNameTemplate は従来どおり gwm-<branch>。
+const DefaultSessionNameTemplate = "gwm-{{.Branch}}"
+
+// SessionNameVars are the variables available in Settings.SessionNameTemplate.
+type SessionNameVars struct {
+	Repo   string // sanitized base name of the main repository directory
+	Branch string // sanitized branch (directory name for a detached worktree)
+	Hash   string // short hash identifying the worktree path
+}
+
+// SessionRecord remembers the session name chosen for a worktree.
+type SessionRecord struct {
+	Branch string `json:"branch"`
+	Path   string `json:"path"`
+	Name   string `json:"name"`
+}
+
+// SessionNameService picks session names from the template, adds the
+// worktree hash when the name is already used by another worktree, and
+// stores the result so later lookups do not depend on the template.
+// A nil *SessionNameService falls back to SessionName without storing.
+type SessionNameService struct {
+	repo    StateRepository
+	tmpl    string
+	repoDir string
+}
+
+func NewSessionNameService(repo StateRepository, settings Settings, repoDir string) *SessionNameService {
+	return &SessionNameService{repo: repo, tmpl: settings.SessionNameTemplate, repoDir: repoDir}
+}
+
+// Lookup returns the stored name for wt.
+func (s *SessionNameService) Lookup(wt WorktreeInfo) (string, bool, error) {
+	if s == nil {
+		return "", false, nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return "", false, err
+	}
+	if r := findSessionRecord(st.Sessions, wt.Path); r != nil {
+		return r.Name, true, nil
+	}
+	return "", false, nil
+}
+
+// Resolve returns the stored name for wt or chooses a new one; Remember
+// stores it once the session exists. taken reports whether a live session
+// with that name belongs to another worktree.
+func (s *SessionNameService) Resolve(wt WorktreeInfo, taken func(name string) (bool, error)) (string, error) {
+	if s == nil {
+		return SessionName(wt), nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return "", err
+	}
+	if r := findSessionRecord(st.Sessions, wt.Path); r != nil {
+		return r.Name, nil
+	}
+
+	vars, lossy := s.vars(wt)
+	name, err := RenderSessionName(s.tmpl, vars)
+	if err != nil {
+		return "", err
+	}
+	hashed := strings.Contains(s.tmpl, ".Hash")
+	if lossy && !hashed {
+		// feat/a.b と feat/ab のように削った文字だけが違うブランチは、どちらかが動いていなくても区別する。
+		name += "-" + vars.Hash
+		hashed = true
+	}
+	inUse := func(n string) (bool, error) {
+		for _, r := range st.Sessions {
+			if r.Name == n && r.Path != wt.Path {
+				return true, nil
+			}
+		}
+		return taken(n)
+	}
+	used, err := inUse(name)
+	if err != nil {
+		return "", err
+	}
+	if used {
+		// 同じ名前になる別ブランチや別リポジトリがあるので、worktree のハッシュで区別する。
+		if !hashed {
+			name += "-" + vars.Hash
+		} else {
+			name += "-" + worktreeHash(s.repoDir, wt.Path, 12)
+		}
+		if used, err = inUse(name); err != nil {
+			return "", err
+		} else if used {
+			return "", fmt.Errorf("session name %s is already used by another worktree", name)
+		}
+	}
+	return name, nil
+}
+
+// Remember stores name as the session of wt.
+func (s *SessionNameService) Remember(wt WorktreeInfo, name string) error {
+	if s == nil {
+		return nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	if r := findSessionRecord(st.Sessions, wt.Path); r != nil {
+		if r.Name == name {
+			return nil
+		}
+		r.Name = name
+	} else {
+		st.Sessions = append(st.Sessions, SessionRecord{Branch: strings.TrimPrefix(wt.Branch, "refs/heads/"), Path: wt.Path, Name: name})
+	}
+	return s.repo.Save(st)
+}
+
+// Forget drops the stored name for wt.
+func (s *SessionNameService) Forget(wt WorktreeInfo) error {
+	if s == nil {
+		return nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	kept := st.Sessions[:0]
+	for _, r := range st.Sessions {
+		if r.Path != wt.Path {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(st.Sessions) {
+		return nil
+	}
+	st.Sessions = kept
+	return s.repo.Save(st)
+}
+
+// Move points the stored name of from at the renamed worktree to, so that
+// a running session keeps belonging to it.
+func (s *SessionNameService) Move(from, to WorktreeInfo) error {
+	if s == nil {
+		return nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	r := findSessionRecord(st.Sessions, from.Path)
+	if r == nil {
+		return nil
+	}
+	r.Branch = strings.TrimPrefix(to.Branch, "refs/heads/")
+	r.Path = to.Path
+	return s.repo.Save(st)
+}
+
+// Records returns every stored session name.
+func (s *SessionNameService) Records() ([]SessionRecord, error) {
+	if s == nil {
+		return nil, nil
+	}
+	st, err := s.repo.Load()
+	return st.Sessions, err
+}
+
+// vars also reports whether sanitizing the branch dropped characters, in
+// which case different branches may share the same Branch.
+func (s *SessionNameService) vars(wt WorktreeInfo) (SessionNameVars, bool) {
+	source := strings.TrimPrefix(wt.Branch, "refs/heads/")
+	branch := SanitizeSessionName(source)
+	if branch == "" {
+		source = filepath.Base(wt.Path)
+		branch = SanitizeSessionName(source)
+	}
+	// "/" は "-" になるだけなので、それ以外に削られた文字があるかを見る。
+	kept := strings.Trim(strings.NewReplacer("/", "-", "\\", "-").Replace(source), "-_")
+	return SessionNameVars{
+		Repo:   SanitizeSessionName(filepath.Base(s.repoDir)),
+		Branch: branch,
+		Hash:   worktreeHash(s.repoDir, wt.Path, 6),
+	}, branch != kept
+}
+
+// RenderSessionName expands tmpl (DefaultSessionNameTemplate when empty).
+// "." and ":" are replaced because tmux uses them in targets.
+func RenderSessionName(tmpl string, vars SessionNameVars) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultSessionNameTemplate
+	}
+	t, err := template.New("sessionName").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid sessionNameTemplate: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("invalid sessionNameTemplate: %w", err)
+	}
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ':':
+			return '-'
+		case ' ', '\t', '\n':
+			return -1
+		}
+		return r
+	}, buf.String())
+	name = strings.Trim(name, "-_")
+	if name == "" {
+		return "", fmt.Errorf("sessionNameTemplate rendered an empty name")
+	}
+	return name, nil
+}
+
+func worktreeHash(repoDir, path string, n int) string {
+	sum := sha1.Sum([]byte(repoDir + "\x00" + path))
+	return hex.EncodeToString(sum[:])[:n]
+}
+
+func findSessionRecord(list []SessionRecord, path string) *SessionRecord {
+	for i := range list {
+		if list[i].Path == path {
+			return &list[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSessionName(t *testing.T) {
+	vars := SessionNameVars{Repo: "api", Branch: "feature-x", Hash: "abc123"}
+	cases := map[string]string{
+		"":                          "gwm-feature-x",
+		"{{.Repo}}/{{.Branch}}":     "api/feature-x",
+		"{{.Repo}}.{{.Hash}}":       "api-abc123",
+		"gwm {{.Branch}}:{{.Hash}}": "gwmfeature-x-abc123",
+	}
+	for tmpl, want := range cases {
+		got, err := RenderSessionName(tmpl, vars)
+		if err != nil || got != want {
+			t.Fatalf("RenderSessionName(%q) = %q, %v; want %q", tmpl, got, err, want)
+		}
+	}
+	for _, bad := range []string{"{{.Nope}}", "{{", "{{if false}}x{{end}}"} {
+		if _, err := RenderSessionName(bad, vars); err == nil {
+			t.Fatalf("RenderSessionName(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSessionNameServiceDisambiguates(t *testing.T) {
+	repo := &memoryState{}
+	s := NewSessionNameService(repo, Settings{}, "/src/api")
+	none := func(string) (bool, error) { return false, nil }
+	resolve := func(wt WorktreeInfo, taken func(string) (bool, error)) string {
+		t.Helper()
+		name, err := s.Resolve(wt, taken)
+		if err != nil {
+			t.Fatalf("Resolve(%s): %v", wt.Branch, err)
+		}
+		if err := s.Remember(wt, name); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+
+	// Resolve だけでは記録しない。セッションを作れなかった名前が残るため。
+	if _, err := s.Resolve(WorktreeInfo{Branch: "main", Path: "/src/api"}, none); err != nil {
+		t.Fatal(err)
+	}
+	if records, _ := s.Records(); len(records) != 0 {
+		t.Fatalf("Resolve should not store the name: %+v", records)
+	}
+
+	first := resolve(WorktreeInfo{Branch: "refs/heads/feat/ab", Path: "/src/api/worktrees/feat/ab"}, none)
+	if first != "gwm-feat-ab" {
+		t.Fatalf("first = %q", first)
+	}
+	// feat/a.b は "." が削られて gwm-feat-ab になるので、衝突が無くてもハッシュを付ける。
+	dotted := NewSessionNameService(&memoryState{}, Settings{}, "/src/api")
+	alone, err := dotted.Resolve(WorktreeInfo{Branch: "feat/a.b", Path: "/src/api/worktrees/feat/a.b"}, none)
+	if err != nil || alone == "gwm-feat-ab" || !strings.HasPrefix(alone, "gwm-feat-ab-") {
+		t.Fatalf("feat/a.b alone = %q, %v", alone, err)
+	}
+	second := resolve(WorktreeInfo{Branch: "feat/a.b", Path: "/src/api/worktrees/feat/a.b"}, none)
+	if second != alone {
+		t.Fatalf("second = %q, want %q", second, alone)
+	}
+	// 別リポジトリの main が既にセッションを持っている。
+	taken := func(n string) (bool, error) { return n == "gwm-main", nil }
+	main := resolve(WorktreeInfo{Branch: "main", Path: "/src/api"}, taken)
+	if main == "gwm-main" {
+		t.Fatalf("main = %q", main)
+	}
+
+	// 保存済みの名前はテンプレートや衝突判定に関係なくそのまま使う。
+	s.tmpl = "{{.Repo}}-{{.Branch}}"
+	again, err := s.Resolve(WorktreeInfo{Branch: "feat/a.b", Path: "/src/api/worktrees/feat/a.b"}, func(string) (bool, error) { return true, nil })
+	if err != nil || again != second {
+		t.Fatalf("again = %q, want %q (%v)", again, second, err)
+	}
+	if name, ok, _ := s.Lookup(WorktreeInfo{Path: "/src/api"}); !ok || name != main {
+		t.Fatalf("Lookup = %q, %v", name, ok)
+	}
+
+	if err := s.Forget(WorktreeInfo{Path: "/src/api"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.Lookup(WorktreeInfo{Path: "/src/api"}); ok {
+		t.Fatal("Forget should drop the record")
+	}
+	if records, _ := s.Records(); len(records) != 2 {
+		t.Fatalf("records = %+v", records)
+	}
+}
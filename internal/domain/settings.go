@@ -1,10 +1,69 @@
 package domain
 
+import "fmt"
+
+// Launcher names accepted by Settings.Launcher.
+const (
+	LauncherAuto   = "auto"
+	LauncherTmux   = "tmux"
+	LauncherZellij = "zellij"
+	LauncherScreen = "screen"
+	LauncherShell  = "shell"
+	LauncherCd     = "cd"
+)
+
+// Values of Settings.TmuxInside.
+const (
+	TmuxInsideSwitch = "switch"
+	TmuxInsideWindow = "window"
+)
+
 // Settings は gwm の起動時に読み込むユーザー設定。
 // フィールドを増やした際もゼロ値で安全に扱えるようにする。
 type Settings struct {
 	// TmuxControlMode を有効にすると tmux を -CC 付きで起動する。
 	TmuxControlMode bool `json:"tmuxControlMode"`
+	// TmuxInside は tmux の中から開いたときの動作。空または "switch" で
+	// worktree のセッションへ switch-client、"window" で現在のセッションに
+	// ウィンドウとして開く。
+	TmuxInside string `json:"tmuxInside,omitempty"`
+	// SessionNameTemplate は tmux のセッション名 (text/template)。
+	// 使える変数は {{.Repo}}、{{.Branch}}、{{.Hash}}。空なら "gwm-{{.Branch}}"。
+	SessionNameTemplate string `json:"sessionNameTemplate,omitempty"`
+	// Launcher は worktree を開く方法。空または "auto" で自動検出
+	// (実行中のマルチプレクサ、なければ tmux・zellij・screen の順、
+	// どれも無ければ $SHELL)。"tmux" / "zellij" / "screen" / "shell" で固定、
+	// "cd" でシェル統合 (gwm shell-init) 経由のディレクトリ移動。
+	Launcher string `json:"launcher,omitempty"`
+	// WorktreePathTemplate は worktree の作成先 (text/template)。
+	// 例: "../{{.Repo}}-worktrees/{{.BranchSlug}}"。空なら worktrees/{{.Branch}}。
+	WorktreePathTemplate string `json:"worktreePathTemplate,omitempty"`
+	// PortBase / PortBlockSize は worktree ごとのポート割り当て。
+	// worktree N には PortBase+N*PortBlockSize から PortBlockSize 個を割り当てる。
+	// 0 のときは 3000 / 10。
+	PortBase      int `json:"portBase,omitempty"`
+	PortBlockSize int `json:"portBlockSize,omitempty"`
+	// Editor は `gwm ui` で worktree を開くコマンド (例: "code -n")。
+	// 空なら $VISUAL、$EDITOR、vi の順に使う。
+	Editor string `json:"editor,omitempty"`
+	// Layout は tmux で新しくセッションを作るときのウィンドウ構成。
+	// 空なら従来どおりウィンドウ 1 つだけ。
+	Layout *SessionLayout `json:"layout,omitempty"`
+	// LayoutOverrides はブランチ名のパターンごとの Layout。先に一致したものを使う。
+	LayoutOverrides []LayoutOverride `json:"layoutOverrides,omitempty"`
+}
+
+// Validate checks the enumerated fields and the layouts.
+func (s Settings) Validate() error {
+	switch s.TmuxInside {
+	case "", TmuxInsideSwitch, TmuxInsideWindow:
+	default:
+		return fmt.Errorf("unsupported tmuxInside: %s", s.TmuxInside)
+	}
+	if _, err := RenderSessionName(s.SessionNameTemplate, SessionNameVars{Repo: "repo", Branch: "branch", Hash: "abc123"}); err != nil {
+		return err
+	}
+	return s.ValidateLayouts()
 }
 
 // DefaultSettings は設定ファイルが存在しない場合に利用するデフォルト値。
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// UsageService records when each worktree was last opened through gwm.
+// A nil *UsageService records nothing.
+type UsageService struct {
+	repo StateRepository
+	now  func() time.Time
+}
+
+func NewUsageService(repo StateRepository) *UsageService {
+	return &UsageService{repo: repo, now: time.Now}
+}
+
+// Touch marks branch as used now.
+func (s *UsageService) Touch(branch string) error {
+	if s == nil {
+		return nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	if st.LastUsed == nil {
+		st.LastUsed = map[string]time.Time{}
+	}
+	st.LastUsed[strings.TrimPrefix(branch, "refs/heads/")] = s.now().UTC().Truncate(time.Second)
+	return s.repo.Save(st)
+}
+
+// Forget drops the record for branch.
+func (s *UsageService) Forget(branch string) error {
+	if s == nil {
+		return nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	if _, ok := st.LastUsed[branch]; !ok {
+		return nil
+	}
+	delete(st.LastUsed, branch)
+	return s.repo.Save(st)
+}
+
+// Rename moves the record of branch to newBranch.
+func (s *UsageService) Rename(branch, newBranch string) error {
+	if s == nil {
+		return nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return err
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	t, ok := st.LastUsed[branch]
+	if !ok {
+		return nil
+	}
+	delete(st.LastUsed, branch)
+	st.LastUsed[strings.TrimPrefix(newBranch, "refs/heads/")] = t
+	return s.repo.Save(st)
+}
+
+// LastUsed returns the recorded times keyed by short branch name.
+func (s *UsageService) LastUsed() (map[string]time.Time, error) {
+	if s == nil {
+		return nil, nil
+	}
+	st, err := s.repo.Load()
+	if err != nil {
+		return nil, err
+	}
+	return st.LastUsed, nil
+}
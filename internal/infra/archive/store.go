@@ -0,0 +1,327 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+const (
+	manifestName = "manifest.json"
+	patchName    = "changes.patch"
+	filesPrefix  = "files/"
+)
+
+// Store implements domain.ArchiveRepository with tar.gz files under
+// repoDir/.gwm/archive.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a Store rooted at repoDir/.gwm/archive.
+func NewStore(repoDir string) *Store {
+	return &Store{dir: filepath.Join(repoDir, ".gwm", "archive")}
+}
+
+// Save writes <branch-slug>-<timestamp>.tar.gz. The manifest comes first so
+// Load does not need to read the whole archive.
+func (s *Store) Save(snap domain.Snapshot, worktreePath string) (string, error) {
+	files, err := collectFiles(worktreePath, snap.Manifest.Files)
+	if err != nil {
+		return "", err
+	}
+	m := snap.Manifest
+	m.Version = domain.ArchiveVersion
+	m.Files = files
+	m.Patch = len(snap.Patch) > 0
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.tar.gz", domain.BranchSlug(m.Branch), m.CreatedAt.Format("20060102-150405"))
+	dst := filepath.Join(s.dir, name)
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	err = writeArchive(f, manifest, snap.Patch, worktreePath, files)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+	return dst, nil
+}
+
+// collectFiles expands directories and drops paths missing from root.
+func collectFiles(root string, paths []string) ([]string, error) {
+	seen := map[string]bool{}
+	var files []string
+	for _, p := range paths {
+		rel := filepath.ToSlash(filepath.Clean(p))
+		if rel == "." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		abs := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Lstat(abs)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !seen[rel] {
+				seen[rel] = true
+				files = append(files, rel)
+			}
+			continue
+		}
+		err = filepath.WalkDir(abs, func(p string, d iofs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			r, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			r = filepath.ToSlash(r)
+			if !seen[r] {
+				seen[r] = true
+				files = append(files, r)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func writeArchive(w io.Writer, manifest, patch []byte, root string, files []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeEntry(tw, manifestName, manifest); err != nil {
+		return err
+	}
+	if len(patch) > 0 {
+		if err := writeEntry(tw, patchName, patch); err != nil {
+			return err
+		}
+	}
+	for _, rel := range files {
+		if err := addFile(tw, root, rel); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func addFile(tw *tar.Writer, root, rel string) error {
+	abs := filepath.Join(root, filepath.FromSlash(rel))
+	info, err := os.Lstat(abs)
+	if err != nil {
+		return err
+	}
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(abs); err != nil {
+			return err
+		}
+	} else if !info.Mode().IsRegular() {
+		return nil
+	}
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = filesPrefix + rel
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if link != "" {
+		return nil
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Load reads the manifest and patch. A bare file name is looked up in the
+// archive directory when it does not exist as given.
+func (s *Store) Load(p string) (domain.Snapshot, error) {
+	var snap domain.Snapshot
+	var manifest []byte
+	err := s.walk(p, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		var err error
+		switch hdr.Name {
+		case manifestName:
+			manifest, err = io.ReadAll(r)
+		case patchName:
+			snap.Patch, err = io.ReadAll(r)
+		default:
+			// manifest と patch はファイル本体より前に書いてある。
+			return false, nil
+		}
+		return true, err
+	})
+	if err != nil {
+		return snap, err
+	}
+	if manifest == nil {
+		return snap, fmt.Errorf("%s: manifest not found", p)
+	}
+	if err := json.Unmarshal(manifest, &snap.Manifest); err != nil {
+		return snap, fmt.Errorf("%s: invalid manifest: %w", p, err)
+	}
+	if snap.Manifest.Version > domain.ArchiveVersion {
+		return snap, fmt.Errorf("%s: unsupported archive version %d", p, snap.Manifest.Version)
+	}
+	return snap, nil
+}
+
+// Extract writes the stored files under dest, overwriting existing ones.
+// Symlinks are created after every regular file, and no entry is written
+// through a symlink, so an archive cannot place files outside dest.
+func (s *Store) Extract(p, dest string) error {
+	type link struct{ target, name string }
+	var links []link
+	err := s.walk(p, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		if !strings.HasPrefix(hdr.Name, filesPrefix) {
+			return true, nil
+		}
+		rel := path.Clean(strings.TrimPrefix(hdr.Name, filesPrefix))
+		if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) {
+			return false, fmt.Errorf("%s: unsafe path in archive: %s", p, hdr.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		switch hdr.Typeflag {
+		case tar.TypeSymlink:
+			links = append(links, link{target: target, name: hdr.Linkname})
+		case tar.TypeReg:
+			if err := prepare(dest, rel, target); err != nil {
+				return false, fmt.Errorf("%s: %w", p, err)
+			}
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return false, err
+			}
+			_, err = io.Copy(f, r)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			return true, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, l := range links {
+		rel, err := filepath.Rel(dest, l.target)
+		if err != nil {
+			return err
+		}
+		if err := prepare(dest, filepath.ToSlash(rel), l.target); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		os.Remove(l.target)
+		if err := os.Symlink(l.name, l.target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepare creates the parent directories of target and removes an existing
+// symlink at target. It fails when a parent of rel under dest is a symlink,
+// which would redirect the write elsewhere.
+func prepare(dest, rel, target string) error {
+	dir := dest
+	parts := strings.Split(rel, "/")
+	for _, part := range parts[:len(parts)-1] {
+		dir = filepath.Join(dir, part)
+		fi, err := os.Lstat(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			if err := os.Mkdir(dir, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("unsafe path in archive: %s goes through the symlink %s", rel, dir)
+		}
+		if !fi.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+	}
+	// 既存のシンボリックリンク（config の symlink など）は書き込み先をたどらずに置き換える。
+	if fi, err := os.Lstat(target); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		return os.Remove(target)
+	}
+	return nil
+}
+
+func (s *Store) walk(p string, fn func(*tar.Header, io.Reader) (bool, error)) error {
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) && filepath.Base(p) == p {
+		f, err = os.Open(filepath.Join(s.dir, p))
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		more, err := fn(hdr, tr)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	repo := t.TempDir()
+	wt := t.TempDir()
+	mustWrite(t, filepath.Join(wt, "notes.txt"), "scratch")
+	mustWrite(t, filepath.Join(wt, "db", "dump.sql"), "select 1;")
+	if err := os.Symlink("notes.txt", filepath.Join(wt, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(repo)
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	snap := domain.Snapshot{
+		Manifest: domain.ArchiveManifest{
+			Branch:    "feature/foo",
+			Commit:    "abc123",
+			CreatedAt: created,
+			Files:     []string{"notes.txt", "db", "link", "missing.txt", "notes.txt"},
+		},
+		Patch: []byte("diff --git a/x b/x\n"),
+	}
+	p, err := s.Save(snap, wt)
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if want := filepath.Join(repo, ".gwm", "archive", "feature-foo-20260102-030405.tar.gz"); p != want {
+		t.Fatalf("archive path = %s, want %s", p, want)
+	}
+
+	got, err := s.Load(filepath.Base(p))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	wantManifest := domain.ArchiveManifest{
+		Version:   domain.ArchiveVersion,
+		Branch:    "feature/foo",
+		Commit:    "abc123",
+		CreatedAt: created,
+		Files:     []string{"notes.txt", "db/dump.sql", "link"},
+		Patch:     true,
+	}
+	if !reflect.DeepEqual(got.Manifest, wantManifest) || string(got.Patch) != string(snap.Patch) {
+		t.Fatalf("Load = %+v", got)
+	}
+
+	dest := t.TempDir()
+	if err := s.Extract(p, dest); err != nil {
+		t.Fatalf("Extract returned error: %v", err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dest, "db", "dump.sql")); string(b) != "select 1;" {
+		t.Fatalf("dump.sql = %q", b)
+	}
+	if l, _ := os.Readlink(filepath.Join(dest, "link")); l != "notes.txt" {
+		t.Fatalf("link target = %q", l)
+	}
+}
+
+func TestExtractDoesNotWriteThroughSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	p := filepath.Join(t.TempDir(), "evil.tar.gz")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	// escape -> 外のディレクトリ、の後に escape/pwned を書かせようとする。
+	if err := tw.WriteHeader(&tar.Header{Name: filesPrefix + "escape", Typeflag: tar.TypeSymlink, Linkname: outside}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: filesPrefix + "escape/pwned", Typeflag: tar.TypeReg, Mode: 0o644, Size: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []interface{ Close() error }{tw, gz, f} {
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := t.TempDir()
+	// シンボリックリンクは通常ファイルの後に作るので、escape は先にディレクトリになる。
+	if err := NewStore(t.TempDir()).Extract(p, dest); err == nil {
+		t.Fatal("expected an error for a symlink over the extracted directory")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "pwned")); err == nil {
+		t.Fatal("archive wrote outside the destination")
+	}
+
+	// 既に dest にあるシンボリックリンクもたどらない。
+	dest = t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dest, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewStore(t.TempDir()).Extract(p, dest); err == nil {
+		t.Fatal("expected an error for a path through an existing symlink")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "pwned")); err == nil {
+		t.Fatal("archive wrote outside the destination")
+	}
+}
+
+func mustWrite(t *testing.T, p, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"os"
@@ -24,22 +25,44 @@ func (s *Store) ensureDir() error {
 	return os.MkdirAll(filepath.Dir(s.path), 0o755)
 }
 
-// Load reads config entries. Empty file or missing file returns empty slice.
-func (s *Store) Load() ([]domain.ConfigEntry, error) {
+// fileFormat is the object form of config.json. The legacy form is a bare
+// array of entries, which is still accepted and kept when no hooks are set.
+type fileFormat struct {
+	Files []domain.ConfigEntry `json:"files"`
+	Hooks domain.HookConfig    `json:"hooks,omitempty"`
+}
+
+func (s *Store) read() (fileFormat, error) {
+	var f fileFormat
 	data, err := os.ReadFile(s.path)
 	if errors.Is(err, os.ErrNotExist) {
-		return []domain.ConfigEntry{}, nil
+		return f, nil
 	}
 	if err != nil {
-		return nil, err
+		return f, err
 	}
-	var entries []domain.ConfigEntry
+	data = bytes.TrimSpace(data)
 	if len(data) == 0 {
-		return []domain.ConfigEntry{}, nil
+		return f, nil
 	}
-	if err := json.Unmarshal(data, &entries); err != nil {
+	if data[0] == '[' {
+		err = json.Unmarshal(data, &f.Files)
+	} else {
+		err = json.Unmarshal(data, &f)
+	}
+	return f, err
+}
+
+// Load reads config entries. Empty file or missing file returns empty slice.
+func (s *Store) Load() ([]domain.ConfigEntry, error) {
+	f, err := s.read()
+	if err != nil {
 		return nil, err
 	}
+	entries := f.Files
+	if entries == nil {
+		return []domain.ConfigEntry{}, nil
+	}
 	for i := range entries {
 		if entries[i].Type == "" {
 			typ, err := detectEntryType(s.repoDir, entries[i].Path)
@@ -52,13 +75,39 @@ func (s *Store) Load() ([]domain.ConfigEntry, error) {
 	return entries, nil
 }
 
-// Save writes entries atomically.
+// LoadHooks reads the "hooks" section. Missing section returns nil.
+func (s *Store) LoadHooks() (domain.HookConfig, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Hooks.Validate(); err != nil {
+		return nil, err
+	}
+	return f.Hooks, nil
+}
+
+// Save writes entries atomically, preserving the other sections.
 func (s *Store) Save(entries []domain.ConfigEntry) error {
+	// 壊れたファイルは従来どおり上書きする（hooks は読めた場合のみ引き継ぐ）。
+	f, err := s.read()
+	if err != nil {
+		f = fileFormat{}
+	}
+	f.Files = entries
+	var v any = entries
+	if len(f.Hooks) > 0 {
+		v = f
+	}
+	return s.write(v)
+}
+
+func (s *Store) write(v any) error {
 	if err := s.ensureDir(); err != nil {
 		return err
 	}
 	tmp := s.path + ".tmp"
-	data, err := json.MarshalIndent(entries, "", "  ")
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -69,6 +118,9 @@ func (s *Store) Save(entries []domain.ConfigEntry) error {
 }
 
 func detectEntryType(repoDir, relPath string) (domain.EntryType, error) {
+	if domain.IsPattern(relPath) {
+		return domain.EntryTypeGlob, nil
+	}
 	info, err := os.Stat(filepath.Join(repoDir, relPath))
 	if err != nil {
 		return "", err
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/example/gwm/internal/domain"
@@ -37,7 +38,7 @@ func TestStoreLoadSave(t *testing.T) {
 		t.Fatalf("len mismatch")
 	}
 	for i := range want {
-		if got[i] != want[i] {
+		if !reflect.DeepEqual(got[i], want[i]) {
 			t.Fatalf("entry mismatch: %v vs %v", got[i], want[i])
 		}
 	}
@@ -67,3 +68,40 @@ func TestStoreLoadSave(t *testing.T) {
 		t.Fatalf("type not inferred: %+v", loaded)
 	}
 }
+
+func TestStoreHooksSectionIsPreserved(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir)
+	path := filepath.Join(dir, ".gwm", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("prepare dir: %v", err)
+	}
+	content := `{"files": [], "hooks": {"post-create": [{"command": "npm ci", "timeout": "5m", "onFailure": "warn"}]}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write err: %v", err)
+	}
+
+	if err := s.Save([]domain.ConfigEntry{{Path: "a.txt", Mode: domain.ModeCopy, Type: domain.EntryTypeFile}}); err != nil {
+		t.Fatalf("save err: %v", err)
+	}
+	hooks, err := s.LoadHooks()
+	if err != nil {
+		t.Fatalf("load hooks err: %v", err)
+	}
+	got := hooks[domain.HookPostCreate]
+	if len(got) != 1 || got[0].Command != "npm ci" || got[0].OnFailure != domain.HookWarn {
+		t.Fatalf("hooks not preserved: %+v", hooks)
+	}
+	entries, err := s.Load()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("entries not saved: %+v, %v", entries, err)
+	}
+
+	bad := `{"hooks": {"post-create": [{"command": ""}]}}`
+	if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+		t.Fatalf("write err: %v", err)
+	}
+	if _, err := s.LoadHooks(); err == nil {
+		t.Fatalf("expected validation error for empty command")
+	}
+}
@@ -1,10 +1,15 @@
 package fs
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	iofs "io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"text/template"
 
 	"github.com/example/gwm/internal/domain"
 )
@@ -17,8 +22,9 @@ func NewOperator(repoDir string) *Operator {
 	return &Operator{repoDir: repoDir}
 }
 
-// Deploy copies or symlinks files defined in entries into worktreePath.
-func (o *Operator) Deploy(entries []domain.ConfigEntry, worktreePath string) error {
+// Deploy copies, symlinks or renders files defined in entries into worktreePath.
+// Glob entries are expanded against the repository at deploy time.
+func (o *Operator) Deploy(entries []domain.ConfigEntry, worktreePath string, vars domain.TemplateVars) error {
 	for _, e := range entries {
 		if e.Type == "" {
 			typ, err := detectEntryType(o.repoDir, e.Path)
@@ -30,36 +36,147 @@ func (o *Operator) Deploy(entries []domain.ConfigEntry, worktreePath string) err
 		if err := e.Validate(); err != nil {
 			return err
 		}
-		src := filepath.Join(o.repoDir, e.Path)
-		dst := filepath.Join(worktreePath, e.Path)
-		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		if e.Type != domain.EntryTypeGlob {
+			if err := o.deployPath(e.Path, e.Type, e.Mode, worktreePath, vars); err != nil {
+				return err
+			}
+			continue
+		}
+		matches, err := o.Expand(e)
+		if err != nil {
 			return err
 		}
-		switch e.Mode {
-		case domain.ModeCopy:
-			if e.Type == domain.EntryTypeDir {
-				if err := copyDir(src, dst); err != nil {
-					return err
-				}
-			} else {
-				if err := copyFile(src, dst); err != nil {
-					return err
-				}
-			}
-		case domain.ModeSymlink:
-			if err := os.RemoveAll(dst); err != nil {
+		for _, rel := range matches {
+			typ, err := detectEntryType(o.repoDir, rel)
+			if err != nil {
 				return err
 			}
-			if err := os.Symlink(src, dst); err != nil {
+			if err := o.deployPath(rel, typ, e.Mode, worktreePath, vars); err != nil {
 				return err
 			}
-		default:
-			return fmt.Errorf("unknown mode: %s", e.Mode)
 		}
 	}
 	return nil
 }
 
+func (o *Operator) deployPath(rel string, typ domain.EntryType, mode domain.Mode, worktreePath string, vars domain.TemplateVars) error {
+	src := filepath.Join(o.repoDir, rel)
+	dst := filepath.Join(worktreePath, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	switch mode {
+	case domain.ModeCopy:
+		if typ == domain.EntryTypeDir {
+			return copyDir(src, dst)
+		}
+		return copyFile(src, dst)
+	case domain.ModeSymlink:
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		return os.Symlink(src, dst)
+	case domain.ModeTemplate:
+		if typ == domain.EntryTypeDir {
+			return fmt.Errorf("template mode requires a file: %s", rel)
+		}
+		return renderFile(src, dst, rel, vars)
+	default:
+		return fmt.Errorf("unknown mode: %s", mode)
+	}
+}
+
+// Expand returns the repository-relative paths entry currently covers.
+// A literal entry expands to itself; a glob entry is matched by walking the
+// repository, skipping .git, .gwm and nested worktrees/repositories.
+// A matched directory is returned as a whole and not descended into.
+func (o *Operator) Expand(e domain.ConfigEntry) ([]string, error) {
+	if !domain.IsPattern(e.Path) {
+		return []string{filepath.ToSlash(filepath.Clean(e.Path))}, nil
+	}
+	root := filepath.Join(o.repoDir, domain.PatternRoot(e.Path))
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	var matches []string
+	err := filepath.WalkDir(root, func(p string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(o.repoDir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() && skipDir(p, d.Name()) {
+			return filepath.SkipDir
+		}
+		if e.Excluded(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ok, err := domain.MatchPattern(e.Path, rel)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		matches = append(matches, rel)
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// skipDir は展開対象外のディレクトリを判定する。.git を持つディレクトリは
+// 別の worktree やサブモジュールなので中身を拾わない。
+func skipDir(path, name string) bool {
+	if name == ".git" || name == ".gwm" {
+		return true
+	}
+	_, err := os.Lstat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// renderFile executes src as a text/template and writes the result to dst
+// with the same permissions. Unknown variables are an error.
+func renderFile(src, dst, name string, vars domain.TemplateVars) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return fmt.Errorf("template %s: %w", name, err)
+	}
+	// symlink が残っているとリンク先を書き換えてしまうので先に消す。
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, buf.Bytes(), info.Mode().Perm())
+}
+
 func copyFile(src, dst string) error {
 	in, err := os.Open(src)
 	if err != nil {
@@ -116,6 +233,9 @@ func copyDir(src, dst string) error {
 }
 
 func detectEntryType(repoDir, relPath string) (domain.EntryType, error) {
+	if domain.IsPattern(relPath) {
+		return domain.EntryTypeGlob, nil
+	}
 	info, err := os.Stat(filepath.Join(repoDir, relPath))
 	if err != nil {
 		return "", err
@@ -0,0 +1,95 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestExpandGlobWithExclude(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".env.local"), "root")
+	writeFile(t, filepath.Join(repo, "apps/web/.env.local"), "web")
+	writeFile(t, filepath.Join(repo, "legacy/old/.env.local"), "old")
+	// 別 worktree (.git ファイルを持つ) の中身は拾わない。
+	writeFile(t, filepath.Join(repo, "worktrees/foo/.git"), "gitdir: x")
+	writeFile(t, filepath.Join(repo, "worktrees/foo/.env.local"), "other")
+
+	o := NewOperator(repo)
+	e := domain.ConfigEntry{Path: "**/.env.local", Mode: domain.ModeCopy, Type: domain.EntryTypeGlob, Exclude: []string{"legacy/**"}}
+	got, err := o.Expand(e)
+	if err != nil {
+		t.Fatalf("Expand returned error: %v", err)
+	}
+	want := []string{".env.local", "apps/web/.env.local"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expand = %v, want %v", got, want)
+	}
+
+	wt := t.TempDir()
+	if err := o.Deploy([]domain.ConfigEntry{e}, wt, domain.TemplateVars{}); err != nil {
+		t.Fatalf("Deploy returned error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(wt, "apps/web/.env.local"))
+	if err != nil || string(data) != "web" {
+		t.Fatalf("deployed file = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(wt, "legacy/old/.env.local")); err == nil {
+		t.Fatalf("excluded file should not be deployed")
+	}
+}
+
+func TestExpandMatchesDirectoriesAsWhole(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "packages/ui/node_modules/.cache/a"), "a")
+	writeFile(t, filepath.Join(repo, "packages/api/node_modules/.cache/b"), "b")
+
+	o := NewOperator(repo)
+	got, err := o.Expand(domain.ConfigEntry{Path: "packages/*/node_modules/.cache", Mode: domain.ModeSymlink, Type: domain.EntryTypeGlob})
+	if err != nil {
+		t.Fatalf("Expand returned error: %v", err)
+	}
+	want := []string{"packages/api/node_modules/.cache", "packages/ui/node_modules/.cache"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expand = %v, want %v", got, want)
+	}
+}
+
+func TestDeployTemplateMode(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".env"), "PORT=30{{printf \"%02d\" .Index}}\nCOMPOSE_PROJECT_NAME={{.BranchSlug}}\n")
+
+	o := NewOperator(repo)
+	wt := t.TempDir()
+	vars := domain.TemplateVars{Branch: "feature/foo", BranchSlug: "feature-foo", WorktreePath: wt, Index: 3}
+	e := domain.ConfigEntry{Path: ".env", Mode: domain.ModeTemplate, Type: domain.EntryTypeFile}
+	if err := o.Deploy([]domain.ConfigEntry{e}, wt, vars); err != nil {
+		t.Fatalf("Deploy returned error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(wt, ".env"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if want := "PORT=3003\nCOMPOSE_PROJECT_NAME=feature-foo\n"; string(data) != want {
+		t.Fatalf("rendered = %q, want %q", data, want)
+	}
+
+	writeFile(t, filepath.Join(repo, "bad.tmpl"), "{{.Missing}}")
+	bad := domain.ConfigEntry{Path: "bad.tmpl", Mode: domain.ModeTemplate, Type: domain.EntryTypeFile}
+	if err := o.Deploy([]domain.ConfigEntry{bad}, wt, vars); err == nil {
+		t.Fatalf("expected error for unknown variable")
+	}
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// FindRepoRoot returns the main working tree of the repository containing dir,
+// even when dir is a subdirectory or inside a linked worktree. .gwm lives there.
+// For a bare repository the repository directory itself is returned.
+func FindRepoRoot(dir string) (string, error) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %s", dir)
+	}
+	common := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(dir, common)
+	}
+	common, err = filepath.Abs(common)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(common); err == nil {
+		common = resolved
+	}
+
+	bare, err := exec.Command("git", "-C", dir, "rev-parse", "--is-bare-repository").Output()
+	if err == nil && strings.TrimSpace(string(bare)) == "true" {
+		return common, nil
+	}
+	// 通常の checkout では common dir は <root>/.git になる。
+	if filepath.Base(common) == ".git" {
+		return filepath.Dir(common), nil
+	}
+	// core.worktree や GIT_DIR 付きの構成は show-toplevel に任せる。
+	top, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine repository root: %w", err)
+	}
+	return strings.TrimSpace(string(top)), nil
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v (%s)", args, err, out)
+	}
+}
+
+func TestFindRepoRoot(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, root, "init", "-q")
+	runGit(t, root, "commit", "-q", "--allow-empty", "-m", "init")
+	if err := os.MkdirAll(filepath.Join(root, "src", "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wt := filepath.Join(root, "worktrees", "foo")
+	runGit(t, root, "worktree", "add", "-q", "-b", "foo", wt)
+	if err := os.MkdirAll(filepath.Join(wt, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{root, filepath.Join(root, "src", "pkg"), wt, filepath.Join(wt, "sub")} {
+		got, err := FindRepoRoot(dir)
+		if err != nil {
+			t.Fatalf("FindRepoRoot(%s) error: %v", dir, err)
+		}
+		if got != root {
+			t.Fatalf("FindRepoRoot(%s) = %s, want %s", dir, got, root)
+		}
+	}
+
+	if _, err := FindRepoRoot(t.TempDir()); err == nil {
+		t.Fatalf("expected error outside a repository")
+	}
+}
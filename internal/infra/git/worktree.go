@@ -3,21 +3,26 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/example/gwm/internal/domain"
 )
 
 // WorktreeClient implements domain.WorktreeService using git CLI.
 type WorktreeClient struct {
-	repoDir string
+	repoDir      string
+	pathTemplate string
 }
 
-func NewWorktreeClient(repoDir string) *WorktreeClient {
-	return &WorktreeClient{repoDir: repoDir}
+func NewWorktreeClient(repoDir string, settings domain.Settings) *WorktreeClient {
+	return &WorktreeClient{repoDir: repoDir, pathTemplate: settings.WorktreePathTemplate}
 }
 
 func (c *WorktreeClient) BranchExists(branch string) (bool, error) {
@@ -43,14 +48,102 @@ func (c *WorktreeClient) defaultBranch() string {
 	return parts[len(parts)-1]
 }
 
-func (c *WorktreeClient) CreateBranch(branch string) error {
-	base := c.defaultBranch()
-	cmd := exec.Command("git", "-C", c.repoDir, "branch", branch, base)
-	return cmd.Run()
+func (c *WorktreeClient) CreateBranch(branch, base string, track bool) (string, error) {
+	if base == "" {
+		base = c.defaultBranch()
+	}
+	if ok, err := c.BranchExists(base + "^{commit}"); err != nil {
+		return "", err
+	} else if !ok {
+		return "", fmt.Errorf("base ref not found: %s (run git fetch if it is a remote branch)", base)
+	}
+
+	args := []string{"-C", c.repoDir, "branch"}
+	if track {
+		// --track はリモート追跡ブランチかローカルブランチにしか付けられない。
+		remote, err := c.BranchExists("refs/remotes/" + base)
+		if err != nil {
+			return "", err
+		}
+		local, err := c.BranchExists("refs/heads/" + base)
+		if err != nil {
+			return "", err
+		}
+		if !remote && !local {
+			return "", fmt.Errorf("cannot track %s: not a branch", base)
+		}
+		args = append(args, "--track")
+	} else {
+		args = append(args, "--no-track")
+	}
+	args = append(args, branch, base)
+
+	cmd := exec.Command("git", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git branch failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return base, nil
+}
+
+func (c *WorktreeClient) DeleteBranch(branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	cmd := exec.Command("git", "-C", c.repoDir, "branch", flag, strings.TrimPrefix(branch, "refs/heads/"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git branch %s failed: %w (%s)", flag, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (c *WorktreeClient) Upstream(branch string) (string, string, bool, error) {
+	ref := "refs/heads/" + strings.TrimPrefix(branch, "refs/heads/")
+	out, err := exec.Command("git", "-C", c.repoDir, "for-each-ref",
+		"--format=%(upstream:remotename)%00%(upstream:remoteref)", ref).Output()
+	if err != nil {
+		return "", "", false, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+	remote, name, _ := strings.Cut(strings.TrimSpace(string(out)), "\x00")
+	if remote == "" || name == "" {
+		return "", "", false, nil
+	}
+	return remote, strings.TrimPrefix(name, "refs/heads/"), true, nil
+}
+
+// UnpushedCommits counts the commits of branch that are neither pushed nor
+// merged into HEAD, mirroring the check of `git branch -d`; without remotes
+// only HEAD counts.
+func (c *WorktreeClient) UnpushedCommits(branch string, ignore ...string) (int, error) {
+	args := []string{"-C", c.repoDir, "rev-list", "--count", strings.TrimPrefix(branch, "refs/heads/"), "--not", "HEAD"}
+	// --exclude は直後の --remotes にだけ効き、"origin/foo" の形で指定する。
+	for _, ref := range ignore {
+		args = append(args, "--exclude="+ref)
+	}
+	args = append(args, "--remotes")
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return 0, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+func (c *WorktreeClient) DeleteRemoteBranch(remote, name string) error {
+	cmd := exec.Command("git", "-C", c.repoDir, "push", remote, "--delete", name)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git push --delete failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
 }
 
 func (c *WorktreeClient) AddWorktree(branch string) (string, error) {
-	path := filepath.Join(c.repoDir, "worktrees", branch)
+	path, err := domain.RenderWorktreePath(c.pathTemplate, c.repoDir, branch)
+	if err != nil {
+		return "", err
+	}
+	if err := c.checkPathCollision(path); err != nil {
+		return "", err
+	}
 	if err := exec.Command("mkdir", "-p", filepath.Dir(path)).Run(); err != nil {
 		return "", err
 	}
@@ -61,36 +154,352 @@ func (c *WorktreeClient) AddWorktree(branch string) (string, error) {
 	return path, nil
 }
 
+// checkPathCollision rejects paths already used on disk or registered by git
+// (e.g. "feat/a" and "feat-a" rendering to the same slug).
+func (c *WorktreeClient) checkPathCollision(path string) error {
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("worktree path already exists: %s", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	list, err := c.ListWorktrees()
+	if err != nil {
+		return err
+	}
+	for _, wt := range list {
+		if filepath.Clean(wt.Path) == path {
+			return fmt.Errorf("worktree path %s is already registered for %s", path, wt.Branch)
+		}
+	}
+	return nil
+}
+
+// ListWorktrees parses `git worktree list --porcelain -z`. IsCurrent marks
+// the worktree containing the process working directory.
 func (c *WorktreeClient) ListWorktrees() ([]domain.WorktreeInfo, error) {
-	cmd := exec.Command("git", "-C", c.repoDir, "worktree", "list", "--porcelain")
-	out, err := cmd.Output()
+	sep := byte(0)
+	out, err := exec.Command("git", "-C", c.repoDir, "worktree", "list", "--porcelain", "-z").Output()
 	if err != nil {
-		return nil, err
+		// -z は git 2.36 以降。古い git では改行区切りで読む。
+		sep = '\n'
+		out, err = exec.Command("git", "-C", c.repoDir, "worktree", "list", "--porcelain").Output()
+		if err != nil {
+			return nil, err
+		}
 	}
-	sc := bufio.NewScanner(bytes.NewReader(out))
+	list := parsePorcelain(out, sep)
+
+	if top := currentToplevel(); top != "" {
+		for i := range list {
+			if samePath(list[i].Path, top) {
+				list[i].IsCurrent = true
+			}
+		}
+	}
+	return list, nil
+}
+
+// parsePorcelain parses porcelain output whose lines end with sep.
+// Records are separated by an empty line.
+func parsePorcelain(out []byte, sep byte) []domain.WorktreeInfo {
 	var list []domain.WorktreeInfo
 	var current domain.WorktreeInfo
+	flush := func() {
+		if current.Path != "" {
+			list = append(list, current)
+		}
+		current = domain.WorktreeInfo{}
+	}
+	for _, line := range strings.Split(string(out), string(sep)) {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "":
+			flush()
+		case "worktree":
+			flush()
+			current.Path = value
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = value
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+			current.PrunableReason = value
+		}
+	}
+	flush()
+	return list
+}
+
+// currentToplevel returns the worktree root of the process working directory,
+// or "" outside a repository.
+func currentToplevel() string {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func samePath(a, b string) bool {
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		a = ra
+	}
+	if rb, err := filepath.EvalSymlinks(b); err == nil {
+		b = rb
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+func (c *WorktreeClient) BranchStatuses(mergedInto string) (map[string]domain.BranchStatus, error) {
+	def := c.defaultBranch()
+	if mergedInto == "" {
+		mergedInto = def
+	}
+	out, err := exec.Command("git", "-C", c.repoDir, "for-each-ref",
+		"--format=%(refname:short)%00%(upstream:track)%00%(committerdate:unix)", "refs/heads").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+	statuses := map[string]domain.BranchStatus{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		var st domain.BranchStatus
+		st.UpstreamGone = fields[1] == "[gone]"
+		if sec, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			st.LastCommit = time.Unix(sec, 0)
+		}
+		statuses[fields[0]] = st
+	}
+
+	merged, err := exec.Command("git", "-C", c.repoDir, "for-each-ref",
+		"--format=%(refname:short)", "--merged", mergedInto, "refs/heads").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref --merged %s failed: %w (%s)", mergedInto, err, strings.TrimSpace(string(merged)))
+	}
+	for _, name := range strings.Fields(string(merged)) {
+		st := statuses[name]
+		st.Merged = true
+		statuses[name] = st
+	}
+	if st, ok := statuses[def]; ok {
+		st.Default = true
+		statuses[def] = st
+	}
+	return statuses, nil
+}
+
+func (c *WorktreeClient) PruneWorktrees() error {
+	cmd := exec.Command("git", "-C", c.repoDir, "worktree", "prune")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree prune failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Describe runs `git status --porcelain=v2 --branch` inside the worktree.
+func (c *WorktreeClient) Describe(wt domain.WorktreeInfo) (domain.WorktreeInfo, error) {
+	cmd := exec.Command("git", "-C", wt.Path, "status", "--porcelain=v2", "--branch")
+	out, err := cmd.Output()
+	if err != nil {
+		return wt, fmt.Errorf("git status failed in %s: %w", wt.Path, err)
+	}
+	parseStatus(out, &wt)
+	return wt, nil
+}
+
+func parseStatus(out []byte, wt *domain.WorktreeInfo) {
+	sc := bufio.NewScanner(bytes.NewReader(out))
 	for sc.Scan() {
 		line := sc.Text()
-		if strings.HasPrefix(line, "worktree ") {
-			if current.Path != "" {
-				list = append(list, current)
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			wt.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &wt.Ahead, &wt.Behind)
+		case strings.HasPrefix(line, "#"):
+		case line != "":
+			wt.Dirty = true
+		}
+	}
+}
+
+func (c *WorktreeClient) Inspect(wt domain.WorktreeInfo) (domain.RemovalInspection, error) {
+	branch := strings.TrimPrefix(wt.Branch, "refs/heads/")
+	r := domain.RemovalInspection{Branch: branch, Path: wt.Path}
+	if _, err := os.Stat(wt.Path); err == nil {
+		out, err := exec.Command("git", "-C", wt.Path, "status", "--porcelain=v1", "-z", "--untracked-files=all").Output()
+		if err != nil {
+			return r, fmt.Errorf("git status failed in %s: %w", wt.Path, err)
+		}
+		r.Changed, r.Untracked = parseShortStatus(out)
+	}
+	if branch == "" {
+		return r, nil
+	}
+	out, err := exec.Command("git", "-C", c.repoDir, "stash", "list", "--format=%gd: %gs").Output()
+	if err != nil {
+		return r, fmt.Errorf("git stash list failed: %w", err)
+	}
+	r.Stashes = filterStashes(out, branch)
+	if r.Unpushed, err = c.UnpushedCommits(branch); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+func (c *WorktreeClient) CaptureChanges(path string) (domain.ChangeSet, error) {
+	var cs domain.ChangeSet
+	out, err := exec.Command("git", "-C", path, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return cs, fmt.Errorf("git rev-parse HEAD failed in %s: %w", path, err)
+	}
+	cs.Commit = strings.TrimSpace(string(out))
+	// --full-index は restore 時の --3way に必要。
+	if cs.Patch, err = exec.Command("git", "-C", path, "diff", "HEAD", "--binary", "--full-index").Output(); err != nil {
+		return cs, fmt.Errorf("git diff failed in %s: %w", path, err)
+	}
+	out, err = exec.Command("git", "-C", path, "ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return cs, fmt.Errorf("git ls-files failed in %s: %w", path, err)
+	}
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			cs.Untracked = append(cs.Untracked, f)
+		}
+	}
+	return cs, nil
+}
+
+func (c *WorktreeClient) ApplyPatch(path string, patch []byte, threeWay bool) error {
+	args := []string{"-C", path, "apply", "--whitespace=nowarn"}
+	if threeWay {
+		args = append(args, "--3way")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = bytes.NewReader(patch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git apply failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (c *WorktreeClient) Head(branch string) (string, error) {
+	ref := "refs/heads/" + strings.TrimPrefix(branch, "refs/heads/")
+	out, err := exec.Command("git", "-C", c.repoDir, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("branch %s not found", branch)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *WorktreeClient) LastCommit(path string) (domain.Commit, error) {
+	out, err := exec.Command("git", "-C", path, "log", "-1", "--format=%H%x00%s%x00%an%x00%aI").Output()
+	if err != nil {
+		return domain.Commit{}, fmt.Errorf("git log failed in %s: %w", path, err)
+	}
+	return parseCommit(out), nil
+}
+
+func parseCommit(out []byte) domain.Commit {
+	f := strings.SplitN(strings.TrimRight(string(out), "\n"), "\x00", 4)
+	for len(f) < 4 {
+		f = append(f, "")
+	}
+	date, _ := time.Parse(time.RFC3339, f[3])
+	return domain.Commit{Hash: f[0], Subject: f[1], Author: f[2], Date: date}
+}
+
+func (c *WorktreeClient) ShortStatus(path string) ([]string, error) {
+	out, err := exec.Command("git", "-C", path, "status", "--short").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed in %s: %w", path, err)
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, nil
+}
+
+// parseShortStatus splits `git status --porcelain=v1 -z` into changed and untracked paths.
+func parseShortStatus(out []byte) (changed, untracked []string) {
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if len(f) < 4 {
+			continue
+		}
+		code, path := f[:2], f[3:]
+		switch {
+		case code == "??":
+			untracked = append(untracked, path)
+		case code == "!!":
+		default:
+			changed = append(changed, path)
+			// rename/copy は元のパスが次のフィールドに続く。
+			if code[0] == 'R' || code[0] == 'C' {
+				i++
 			}
-			current = domain.WorktreeInfo{Path: strings.TrimPrefix(line, "worktree ")}
-		} else if strings.HasPrefix(line, "branch ") {
-			current.Branch = strings.TrimPrefix(line, "branch ")
-		} else if strings.HasPrefix(line, "bare") {
-			// ignore
-		} else if strings.HasPrefix(line, "detached") {
-			current.Branch = "(detached)"
-		} else if strings.HasPrefix(line, "HEAD ") {
-			current.IsCurrent = true
 		}
 	}
-	if current.Path != "" {
-		list = append(list, current)
+	return changed, untracked
+}
+
+// filterStashes keeps the stash entries created on branch
+// ("WIP on <branch>: ..." or "On <branch>: ...").
+func filterStashes(out []byte, branch string) []string {
+	var stashes []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		_, subject, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(subject, "WIP on "+branch+": ") || strings.HasPrefix(subject, "On "+branch+": ") {
+			stashes = append(stashes, line)
+		}
 	}
-	return list, sc.Err()
+	return stashes
+}
+
+// RenameWorktree renames the branch with `git branch -m` and moves the
+// worktree with `git worktree move`. The branch gets its old name back when
+// the move fails.
+func (c *WorktreeClient) RenameWorktree(wt domain.WorktreeInfo, newBranch string) (string, error) {
+	branch := strings.TrimPrefix(wt.Branch, "refs/heads/")
+	newBranch = strings.TrimPrefix(newBranch, "refs/heads/")
+	path, err := domain.RenderWorktreePath(c.pathTemplate, c.repoDir, newBranch)
+	if err != nil {
+		return "", err
+	}
+	if err := c.checkPathCollision(path); err != nil {
+		return "", err
+	}
+	if out, err := exec.Command("git", "-C", c.repoDir, "branch", "-m", branch, newBranch).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git branch -m failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		exec.Command("git", "-C", c.repoDir, "branch", "-m", newBranch, branch).Run()
+		return "", err
+	}
+	if out, err := exec.Command("git", "-C", c.repoDir, "worktree", "move", wt.Path, path).CombinedOutput(); err != nil {
+		// ブランチ名だけ変わった状態を残さない。
+		exec.Command("git", "-C", c.repoDir, "branch", "-m", newBranch, branch).Run()
+		return "", fmt.Errorf("git worktree move failed: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return path, nil
 }
 
 func (c *WorktreeClient) RemoveWorktree(branch string, force bool) (string, error) {
@@ -0,0 +1,194 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestParsePorcelainNUL(t *testing.T) {
+	records := []string{
+		"worktree /repo\x00HEAD 1111\x00branch refs/heads/main\x00",
+		"worktree /repo/worktrees/odd\nname\x00HEAD 2222\x00detached\x00locked moved to\nusb\x00",
+		"worktree /repo/worktrees/gone\x00HEAD 3333\x00branch refs/heads/gone\x00prunable gitdir file points to non-existent location\x00",
+		"worktree /bare.git\x00bare\x00",
+	}
+	out := []byte(strings.Join(records, "\x00") + "\x00")
+
+	got := parsePorcelain(out, 0)
+	want := []domain.WorktreeInfo{
+		{Path: "/repo", Head: "1111", Branch: "refs/heads/main"},
+		{Path: "/repo/worktrees/odd\nname", Head: "2222", Detached: true, Locked: true, LockReason: "moved to\nusb"},
+		{Path: "/repo/worktrees/gone", Head: "3333", Branch: "refs/heads/gone", Prunable: true, PrunableReason: "gitdir file points to non-existent location"},
+		{Path: "/bare.git", Bare: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parsePorcelain =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParsePorcelainNewline(t *testing.T) {
+	out := []byte("worktree /repo\nHEAD 1111\nbranch refs/heads/main\n\nworktree /repo/wt\nHEAD 2222\nbranch refs/heads/foo\nlocked\n\n")
+
+	got := parsePorcelain(out, '\n')
+	if len(got) != 2 {
+		t.Fatalf("expected 2 worktrees, got %+v", got)
+	}
+	if got[0].IsCurrent || got[1].IsCurrent {
+		t.Fatalf("IsCurrent must not be derived from HEAD lines: %+v", got)
+	}
+	if !got[1].Locked || got[1].LockReason != "" || got[1].Branch != "refs/heads/foo" {
+		t.Fatalf("unexpected second worktree: %+v", got[1])
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	out := []byte("# branch.oid abc\n# branch.head foo\n# branch.upstream origin/foo\n# branch.ab +2 -5\n? new.txt\n")
+	var wt domain.WorktreeInfo
+	parseStatus(out, &wt)
+	if wt.Upstream != "origin/foo" || wt.Ahead != 2 || wt.Behind != 5 || !wt.Dirty {
+		t.Fatalf("unexpected status: %+v", wt)
+	}
+}
+
+func TestParseShortStatus(t *testing.T) {
+	out := []byte(" M a.go\x00R  new.go\x00old.go\x00?? notes/todo.txt\x00A  b.go\x00")
+	changed, untracked := parseShortStatus(out)
+	if !reflect.DeepEqual(changed, []string{"a.go", "new.go", "b.go"}) {
+		t.Fatalf("changed = %q", changed)
+	}
+	if !reflect.DeepEqual(untracked, []string{"notes/todo.txt"}) {
+		t.Fatalf("untracked = %q", untracked)
+	}
+}
+
+func TestFilterStashes(t *testing.T) {
+	out := []byte("stash@{0}: WIP on feature: abc fix\nstash@{1}: On main: keep\nstash@{2}: On feature/x: other\nstash@{3}: On feature: mine\n")
+	got := filterStashes(out, "feature")
+	want := []string{"stash@{0}: WIP on feature: abc fix", "stash@{3}: On feature: mine"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("filterStashes = %q, want %q", got, want)
+	}
+}
+
+func TestParseCommit(t *testing.T) {
+	c := parseCommit([]byte("abc123\x00Fix login: redirect\x00Jane Doe\x002026-03-01T10:00:00+09:00\n"))
+	if c.Hash != "abc123" || c.Subject != "Fix login: redirect" || c.Author != "Jane Doe" {
+		t.Fatalf("unexpected commit: %+v", c)
+	}
+	if c.Date.IsZero() || c.Date.Day() != 1 {
+		t.Fatalf("date not parsed: %v", c.Date)
+	}
+}
+
+func TestUnpushedCommitsWithoutRemotes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	runGit(t, root, "init", "-q", "-b", "main")
+	runGit(t, root, "commit", "-q", "--allow-empty", "-m", "init")
+	runGit(t, root, "branch", "foo")
+	c := NewWorktreeClient(root, domain.Settings{})
+
+	// リモートが無くても、HEAD に含まれるブランチは未 push として数えない。
+	if n, err := c.UnpushedCommits("foo"); err != nil || n != 0 {
+		t.Fatalf("UnpushedCommits(foo) = %d, %v; want 0", n, err)
+	}
+	runGit(t, root, "checkout", "-q", "foo")
+	runGit(t, root, "commit", "-q", "--allow-empty", "-m", "work")
+	runGit(t, root, "checkout", "-q", "main")
+	if n, err := c.UnpushedCommits("refs/heads/foo"); err != nil || n != 1 {
+		t.Fatalf("UnpushedCommits(foo) = %d, %v; want 1", n, err)
+	}
+}
+
+func TestInspectMergedWorktreeWithoutRemotes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	runGit(t, root, "init", "-q", "-b", "main")
+	runGit(t, root, "commit", "-q", "--allow-empty", "-m", "init")
+	wt := root + "/worktrees/foo"
+	runGit(t, root, "worktree", "add", "-q", "-b", "foo", wt)
+	c := NewWorktreeClient(root, domain.Settings{})
+
+	r, err := c.Inspect(domain.WorktreeInfo{Branch: "refs/heads/foo", Path: wt})
+	if err != nil {
+		t.Fatalf("Inspect returned error: %v", err)
+	}
+	if r.Unpushed != 0 || !r.Clean() {
+		t.Fatalf("merged worktree without remotes should be safe to remove: %+v", r)
+	}
+}
+
+func TestBranchStatusesMarksDefaultBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	runGit(t, root, "init", "-q", "-b", "develop")
+	runGit(t, root, "commit", "-q", "--allow-empty", "-m", "init")
+	runGit(t, root, "worktree", "add", "-q", "-b", "main", root+"/worktrees/main")
+	c := NewWorktreeClient(root, domain.Settings{})
+
+	statuses, err := c.BranchStatuses("")
+	if err != nil {
+		t.Fatalf("BranchStatuses returned error: %v", err)
+	}
+	// origin/HEAD が無いので main がデフォルト。main 自身にマージ済みにも見える。
+	if st := statuses["main"]; !st.Default || !st.Merged {
+		t.Fatalf("main = %+v, want default and merged", st)
+	}
+	if statuses["develop"].Default {
+		t.Fatalf("develop should not be the default branch: %+v", statuses["develop"])
+	}
+}
+
+func TestRenameWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, root, "init", "-q", "-b", "main")
+	runGit(t, root, "commit", "-q", "--allow-empty", "-m", "init")
+	old := filepath.Join(root, "worktrees", "feat", "old")
+	runGit(t, root, "worktree", "add", "-q", "-b", "feat/old", old)
+	c := NewWorktreeClient(root, domain.Settings{})
+	wt := domain.WorktreeInfo{Branch: "refs/heads/feat/old", Path: old}
+
+	path, err := c.RenameWorktree(wt, "feat/new")
+	if err != nil {
+		t.Fatalf("RenameWorktree returned error: %v", err)
+	}
+	if want := filepath.Join(root, "worktrees", "feat", "new"); path != want {
+		t.Fatalf("path = %s, want %s", path, want)
+	}
+	list, err := c.ListWorktrees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1].Branch != "refs/heads/feat/new" || list[1].Path != path {
+		t.Fatalf("worktrees after rename = %+v", list)
+	}
+
+	// 移動先が埋まっていればブランチ名も変えない。
+	if err := os.MkdirAll(filepath.Join(root, "worktrees", "taken"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RenameWorktree(list[1], "taken"); err == nil {
+		t.Fatal("expected error for an occupied path")
+	}
+	if ok, _ := c.BranchExists("feat/new"); !ok {
+		t.Fatal("branch should keep its name when the worktree cannot move")
+	}
+}
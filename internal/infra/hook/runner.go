@@ -0,0 +1,70 @@
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// Runner implements domain.HookRunner by running hooks with `sh -c`.
+type Runner struct {
+	out io.Writer
+}
+
+// NewRunner streams hook output to w (通常は os.Stderr。stdout はパス出力用に空けておく)。
+func NewRunner(w io.Writer) *Runner {
+	return &Runner{out: w}
+}
+
+func (r *Runner) Run(h domain.Hook, hc domain.HookContext) error {
+	timeout, err := h.TimeoutDuration()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	cmd.Dir = workDir(hc)
+	cmd.Env = append(os.Environ(),
+		"GWM_EVENT="+string(hc.Event),
+		// gwm cd は refs/heads/ 付きで渡すので、gwm env と同じ短い名前にそろえる。
+		"GWM_BRANCH="+strings.TrimPrefix(hc.Branch, "refs/heads/"),
+		"GWM_WORKTREE_PATH="+hc.WorktreePath,
+		"GWM_REPO_ROOT="+hc.RepoRoot,
+	)
+	cmd.Env = append(cmd.Env, hc.Allocation.Env()...)
+	cmd.Stdin = nil
+	cmd.Stdout = r.out
+	cmd.Stderr = r.out
+	// sh の子プロセス (npm など) もまとめて止められるようプロセスグループを分ける。
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 5 * time.Second
+
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// workDir は worktree が存在すればそこで、無ければ (post-remove など) リポジトリ直下で実行する。
+func workDir(hc domain.HookContext) string {
+	if hc.WorktreePath != "" {
+		if info, err := os.Stat(hc.WorktreePath); err == nil && info.IsDir() {
+			return hc.WorktreePath
+		}
+	}
+	return hc.RepoRoot
+}
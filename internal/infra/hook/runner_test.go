@@ -0,0 +1,38 @@
+package hook
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestRunnerPassesEnvironment(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, branch := range []string{"feature/foo", "refs/heads/feature/foo"} {
+		var buf bytes.Buffer
+		r := NewRunner(&buf)
+		hc := domain.HookContext{Event: domain.HookPostCreate, Branch: branch, WorktreePath: dir, RepoRoot: dir}
+		h := domain.Hook{Command: `echo "$GWM_EVENT $GWM_BRANCH $(pwd)"`}
+		if err := r.Run(h, hc); err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		if got := strings.TrimSpace(buf.String()); !strings.HasPrefix(got, "post-create feature/foo ") {
+			t.Fatalf("unexpected output for %s: %q", branch, got)
+		}
+	}
+}
+
+func TestRunnerTimeoutAndFailure(t *testing.T) {
+	r := NewRunner(&bytes.Buffer{})
+	hc := domain.HookContext{RepoRoot: t.TempDir()}
+
+	if err := r.Run(domain.Hook{Command: "sleep 5", Timeout: "100ms"}, hc); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if err := r.Run(domain.Hook{Command: "exit 3"}, hc); err == nil {
+		t.Fatalf("expected error for failing command")
+	}
+}
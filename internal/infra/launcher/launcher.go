@@ -0,0 +1,57 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/example/gwm/internal/domain"
+	"github.com/example/gwm/internal/infra/screen"
+	"github.com/example/gwm/internal/infra/shell"
+	"github.com/example/gwm/internal/infra/tmux"
+	"github.com/example/gwm/internal/infra/zellij"
+)
+
+// New returns the SessionLauncher named by settings.Launcher, detecting one
+// for "" and "auto". names is used by tmux for session naming.
+func New(settings domain.Settings, names *domain.SessionNameService) (domain.SessionLauncher, error) {
+	name := settings.Launcher
+	if name == "" || name == domain.LauncherAuto {
+		name = Detect(os.Getenv, exec.LookPath)
+	}
+	switch name {
+	case domain.LauncherTmux:
+		return tmux.NewLauncher(settings, names), nil
+	case domain.LauncherZellij:
+		return zellij.NewLauncher(), nil
+	case domain.LauncherScreen:
+		return screen.NewLauncher(), nil
+	case domain.LauncherShell:
+		return shell.NewSpawnLauncher(), nil
+	case domain.LauncherCd:
+		return shell.NewCdLauncher(), nil
+	default:
+		return nil, fmt.Errorf("unknown launcher: %s", settings.Launcher)
+	}
+}
+
+// Detect picks the multiplexer gwm is running inside, otherwise the first
+// installed one of tmux, zellij and screen, otherwise a plain shell.
+func Detect(getenv func(string) string, lookPath func(string) (string, error)) string {
+	// 既にマルチプレクサの中なら、入れ子にならないよう同じものを使う。
+	for _, c := range []struct{ env, name string }{
+		{"TMUX", domain.LauncherTmux},
+		{"ZELLIJ", domain.LauncherZellij},
+		{"STY", domain.LauncherScreen},
+	} {
+		if getenv(c.env) != "" {
+			return c.name
+		}
+	}
+	for _, name := range []string{domain.LauncherTmux, domain.LauncherZellij, domain.LauncherScreen} {
+		if _, err := lookPath(name); err == nil {
+			return name
+		}
+	}
+	return domain.LauncherShell
+}
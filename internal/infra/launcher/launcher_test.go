@@ -0,0 +1,48 @@
+package launcher
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		name      string
+		env       map[string]string
+		installed []string
+		want      string
+	}{
+		{"inside zellij wins over installed tmux", map[string]string{"ZELLIJ": "0"}, []string{"tmux", "zellij"}, domain.LauncherZellij},
+		{"inside screen", map[string]string{"STY": "1.x"}, nil, domain.LauncherScreen},
+		{"tmux first", nil, []string{"screen", "tmux"}, domain.LauncherTmux},
+		{"zellij before screen", nil, []string{"screen", "zellij"}, domain.LauncherZellij},
+		{"shell fallback", nil, nil, domain.LauncherShell},
+	}
+	for _, c := range cases {
+		getenv := func(k string) string { return c.env[k] }
+		lookPath := func(name string) (string, error) {
+			for _, n := range c.installed {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+		if got := Detect(getenv, lookPath); got != c.want {
+			t.Fatalf("%s: Detect = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNewRejectsUnknownLauncher(t *testing.T) {
+	if _, err := New(domain.Settings{Launcher: "byobu"}, nil); err == nil {
+		t.Fatal("expected error for unknown launcher")
+	}
+	for _, name := range []string{domain.LauncherTmux, domain.LauncherZellij, domain.LauncherScreen, domain.LauncherShell, domain.LauncherCd} {
+		if l, err := New(domain.Settings{Launcher: name}, nil); err != nil || l == nil {
+			t.Fatalf("New(%s) = %v, %v", name, l, err)
+		}
+	}
+}
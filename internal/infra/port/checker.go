@@ -0,0 +1,23 @@
+package port
+
+import (
+	"net"
+	"strconv"
+)
+
+// Checker implements domain.PortChecker by trying to listen on the port.
+type Checker struct{}
+
+func NewChecker() Checker {
+	return Checker{}
+}
+
+// InUse reports whether something already listens on the TCP port.
+func (Checker) InUse(port int) bool {
+	ln, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return true
+	}
+	ln.Close()
+	return false
+}
@@ -0,0 +1,22 @@
+package port
+
+import (
+	"net"
+	"testing"
+)
+
+func TestCheckerInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	p := ln.Addr().(*net.TCPAddr).Port
+
+	if !NewChecker().InUse(p) {
+		t.Fatalf("port %d should be reported in use", p)
+	}
+	ln.Close()
+	if NewChecker().InUse(p) {
+		t.Fatalf("port %d should be free after close", p)
+	}
+}
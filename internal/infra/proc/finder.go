@@ -0,0 +1,56 @@
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// Finder implements domain.ProcessFinder by reading /proc/<pid>/cwd.
+// On systems without procfs it finds nothing.
+type Finder struct {
+	root string
+}
+
+func NewFinder() Finder {
+	return Finder{root: "/proc"}
+}
+
+// ProcessesIn lists processes (other than gwm itself) whose cwd is dir or below it.
+func (f Finder) ProcessesIn(dir string) ([]domain.Process, error) {
+	entries, err := os.ReadDir(f.root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	dir = filepath.Clean(dir)
+
+	self := os.Getpid()
+	var procs []domain.Process
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		// 他ユーザーのプロセスや終了済みのものは読めないので無視する。
+		cwd, err := os.Readlink(filepath.Join(f.root, e.Name(), "cwd"))
+		if err != nil || !within(cwd, dir) {
+			continue
+		}
+		comm, _ := os.ReadFile(filepath.Join(f.root, e.Name(), "comm"))
+		procs = append(procs, domain.Process{PID: pid, Command: strings.TrimSpace(string(comm))})
+	}
+	return procs, nil
+}
+
+func within(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
@@ -0,0 +1,38 @@
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestFinderProcessesIn(t *testing.T) {
+	if _, err := os.Stat("/proc/self/cwd"); err != nil {
+		t.Skip("procfs not available")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command("sleep", "5")
+	cmd.Dir = dir
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	procs, err := NewFinder().ProcessesIn(dir)
+	if err != nil {
+		t.Fatalf("ProcessesIn returned error: %v", err)
+	}
+	found := false
+	for _, p := range procs {
+		if p.PID == cmd.Process.Pid {
+			found = p.Command == "sleep"
+		}
+	}
+	if !found {
+		t.Fatalf("sleep (pid %d) not found in %+v", cmd.Process.Pid, procs)
+	}
+
+	if procs, _ := NewFinder().ProcessesIn(t.TempDir()); len(procs) != 0 {
+		t.Fatalf("expected no processes, got %+v", procs)
+	}
+}
@@ -0,0 +1,146 @@
+package screen
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+	"github.com/example/gwm/internal/infra/shell"
+)
+
+// Launcher implements domain.SessionLauncher with GNU screen sessions named
+// like the tmux ones (gwm-<branch>).
+type Launcher struct {
+	// run executes a non-interactive screen command and returns its output.
+	run func(args ...string) (string, error)
+	// attach runs screen in dir on the current terminal.
+	attach func(dir string, args ...string) error
+	// inside reports whether gwm runs inside a screen session.
+	inside func() bool
+}
+
+func NewLauncher() *Launcher {
+	return &Launcher{
+		run: func(args ...string) (string, error) {
+			out, err := exec.Command("screen", args...).CombinedOutput()
+			if err != nil {
+				return string(out), fmt.Errorf("screen %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+			}
+			return string(out), nil
+		},
+		attach: func(dir string, args ...string) error {
+			return shell.Interactive(dir, "screen", args...).Run()
+		},
+		inside: func() bool { return os.Getenv("STY") != "" },
+	}
+}
+
+// Launch attaches to (or creates) the worktree's session. Inside screen the
+// worktree opens as a window of the current session, or in a new region for
+// TargetPane, instead of nesting. opts.Detach starts a detached session.
+func (l *Launcher) Launch(wt domain.WorktreeInfo, opts domain.LaunchOptions) error {
+	path, err := shell.AbsPath(wt)
+	if err != nil {
+		return err
+	}
+	name := sessionName(wt)
+	if opts.Detach {
+		if opts.Target != domain.TargetSession {
+			return fmt.Errorf("screen cannot open a %s in the background", opts.Target)
+		}
+		if l.session(name) != "" {
+			return nil
+		}
+		return l.attach(path, "-dmS", name)
+	}
+	if !l.inside() {
+		if opts.Target != domain.TargetSession {
+			return fmt.Errorf("opening a worktree as a %s requires running inside screen", opts.Target)
+		}
+		if id := l.session(name); id != "" {
+			// -x は他の端末で attach 中でも接続できる。
+			return shell.StartError(l.attach(path, "-x", id))
+		}
+		return shell.StartError(l.attach(path, "-S", name))
+	}
+
+	var cmds [][]string
+	if opts.Target == domain.TargetPane {
+		cmds = append(cmds, []string{"split", "-v"}, []string{"focus"})
+	}
+	cmds = append(cmds, []string{"chdir", path}, []string{"screen", "-t", strings.TrimPrefix(name, "gwm-")})
+	for _, c := range cmds {
+		if _, err := l.run(append([]string{"-X"}, c...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Kill quits the worktree's session when it exists.
+func (l *Launcher) Kill(wt domain.WorktreeInfo) error {
+	id := l.session(sessionName(wt))
+	if id == "" {
+		return nil
+	}
+	_, err := l.run("-S", id, "-X", "quit")
+	return err
+}
+
+func (l *Launcher) HasSession(wt domain.WorktreeInfo) (bool, error) {
+	return l.session(sessionName(wt)) != "", nil
+}
+
+// Windows counts the windows reported by `screen -Q windows` (0 when the
+// installed screen does not support -Q).
+func (l *Launcher) Windows(wt domain.WorktreeInfo) (int, error) {
+	id := l.session(sessionName(wt))
+	if id == "" {
+		return 0, nil
+	}
+	out, err := l.run("-S", id, "-Q", "windows")
+	if err != nil {
+		return 0, nil
+	}
+	// ウィンドウは "0$ bash  1-$ vim" のように空白 2 つ区切りで並ぶ。
+	n := 0
+	for _, w := range strings.Split(strings.TrimSpace(out), "  ") {
+		if strings.TrimSpace(w) != "" {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// session returns the "<pid>.<name>" id of the session called exactly name,
+// or "" when none runs. screen matches -S/-r by prefix, so the id is used as
+// the target instead of the bare name.
+func (l *Launcher) session(name string) string {
+	// screen -ls はセッションがあっても終了コード 1 を返すことがあるので、出力だけを見る。
+	out, _ := l.run("-ls")
+	return parseSessionList(out, name)
+}
+
+// parseSessionList finds name in `screen -ls` output, whose lines look like
+// "\t12345.gwm-foo\t(Detached)".
+func parseSessionList(out, name string) string {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if pid, n, ok := strings.Cut(fields[0], "."); ok && n == name && pid != "" {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+func sessionName(wt domain.WorktreeInfo) string {
+	if name := domain.SessionName(wt); name != "" {
+		return name
+	}
+	return "gwm-session"
+}
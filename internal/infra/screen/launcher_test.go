@@ -0,0 +1,112 @@
+package screen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+const lsOutput = `There are screens on:
+	4242.gwm-feature-foobar	(Detached)
+	4243.gwm-feature-foo	(Attached)
+2 Sockets in /run/screen/S-me.
+`
+
+type fakeScreen struct {
+	calls    []string
+	attached []string
+	inside   bool
+}
+
+func (f *fakeScreen) launcher() *Launcher {
+	return &Launcher{
+		run: func(args ...string) (string, error) {
+			f.calls = append(f.calls, strings.Join(args, " "))
+			if args[0] == "-ls" {
+				return lsOutput, nil
+			}
+			return "", nil
+		},
+		attach: func(dir string, args ...string) error {
+			f.attached = append(f.attached, dir+": "+strings.Join(args, " "))
+			return nil
+		},
+		inside: func() bool { return f.inside },
+	}
+}
+
+func TestParseSessionList(t *testing.T) {
+	if got := parseSessionList(lsOutput, "gwm-feature-foo"); got != "4243.gwm-feature-foo" {
+		t.Fatalf("parseSessionList = %q", got)
+	}
+	if got := parseSessionList(lsOutput, "gwm-feature"); got != "" {
+		t.Fatalf("prefix should not match, got %q", got)
+	}
+}
+
+func TestLauncherAttachesOrCreates(t *testing.T) {
+	f := &fakeScreen{}
+	l := f.launcher()
+
+	if err := l.Launch(domain.WorktreeInfo{Branch: "feature/foo", Path: "/wt/foo"}, domain.LaunchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Launch(domain.WorktreeInfo{Branch: "fix/bar", Path: "/wt/bar"}, domain.LaunchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/wt/foo: -x 4243.gwm-feature-foo", "/wt/bar: -S gwm-fix-bar"}
+	if !reflect.DeepEqual(f.attached, want) {
+		t.Fatalf("attached = %q, want %q", f.attached, want)
+	}
+	if err := l.Launch(domain.WorktreeInfo{Branch: "fix/bar", Path: "/wt/bar"}, domain.LaunchOptions{Target: domain.TargetWindow}); err == nil {
+		t.Fatal("window target outside screen should fail")
+	}
+}
+
+func TestLauncherInsideScreenOpensRegion(t *testing.T) {
+	f := &fakeScreen{inside: true}
+	l := f.launcher()
+
+	if err := l.Launch(domain.WorktreeInfo{Branch: "fix/bar", Path: "/wt/bar"}, domain.LaunchOptions{Target: domain.TargetPane}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"-X split -v", "-X focus", "-X chdir /wt/bar", "-X screen -t fix-bar"}
+	if !reflect.DeepEqual(f.calls, want) || len(f.attached) != 0 {
+		t.Fatalf("calls = %q attached = %q", f.calls, f.attached)
+	}
+}
+
+func TestLauncherKill(t *testing.T) {
+	f := &fakeScreen{}
+	l := f.launcher()
+
+	if err := l.Kill(domain.WorktreeInfo{Branch: "feature/foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Kill(domain.WorktreeInfo{Branch: "gone"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"-ls", "-S 4243.gwm-feature-foo -X quit", "-ls"}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("calls = %q, want %q", f.calls, want)
+	}
+}
+
+func TestLauncherDetachStartsDetachedSession(t *testing.T) {
+	f := &fakeScreen{}
+	l := f.launcher()
+	opts := domain.LaunchOptions{Detach: true}
+
+	if err := l.Launch(domain.WorktreeInfo{Branch: "feature/foo", Path: "/wt/foo"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Launch(domain.WorktreeInfo{Branch: "fix/bar", Path: "/wt/bar"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	// 既に動いている gwm-feature-foo には何もしない。
+	if want := []string{"/wt/bar: -dmS gwm-fix-bar"}; !reflect.DeepEqual(f.attached, want) {
+		t.Fatalf("attached = %q, want %q", f.attached, want)
+	}
+}
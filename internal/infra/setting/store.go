@@ -3,6 +3,7 @@ package setting
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -30,6 +31,9 @@ func Load(repoDir string) (domain.Settings, error) {
 	if err := json.Unmarshal(data, &settings); err != nil {
 		return domain.DefaultSettings(), err
 	}
+	if err := settings.Validate(); err != nil {
+		return domain.DefaultSettings(), fmt.Errorf("%s: %w", path, err)
+	}
 
 	return settings, nil
 }
@@ -3,6 +3,7 @@ package setting
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/example/gwm/internal/domain"
@@ -16,7 +17,7 @@ func TestLoad_ReturnsDefaultWhenMissing(t *testing.T) {
 		t.Fatalf("Load returned error: %v", err)
 	}
 
-	if got != domain.DefaultSettings() {
+	if !reflect.DeepEqual(got, domain.DefaultSettings()) {
 		t.Fatalf("expected default settings, got %+v", got)
 	}
 }
@@ -42,3 +43,37 @@ func TestLoad_ReadsFile(t *testing.T) {
 		t.Fatalf("TmuxControlMode should be true, got %+v", got)
 	}
 }
+
+func TestLoad_ReadsLayouts(t *testing.T) {
+	dir := t.TempDir()
+	gwmDir := filepath.Join(dir, ".gwm")
+	if err := os.MkdirAll(gwmDir, 0o755); err != nil {
+		t.Fatalf("failed to prepare dir: %v", err)
+	}
+	content := `{
+  "layout": {"windows": [{"name": "editor", "command": "nvim"}, {"name": "dev", "panes": [{"split": "vertical", "command": "go test ./..."}]}]},
+  "layoutOverrides": [{"branch": "docs/*", "layout": {"windows": [{"name": "docs", "dir": "docs"}]}}]
+}`
+	if err := os.WriteFile(filepath.Join(gwmDir, "setting.json"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	got, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got.Layout == nil || len(got.Layout.Windows) != 2 || got.Layout.Windows[1].Panes[0].Split != domain.SplitVertical {
+		t.Fatalf("layout not loaded: %+v", got.Layout)
+	}
+	if len(got.LayoutOverrides) != 1 || got.LayoutOverrides[0].Layout.Windows[0].Dir != "docs" {
+		t.Fatalf("overrides not loaded: %+v", got.LayoutOverrides)
+	}
+
+	bad := `{"layout": {"windows": [{"dir": "../outside"}]}}`
+	if err := os.WriteFile(filepath.Join(gwmDir, "setting.json"), []byte(bad), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Fatal("expected an error for a layout dir outside the worktree")
+	}
+}
@@ -0,0 +1,46 @@
+package shell
+
+import "fmt"
+
+const posixInit = `# gwm shell integration (eval "$(gwm shell-init %[1]s)")
+gwm() {
+  local __gwm_cd_file __gwm_status __gwm_dir
+  __gwm_cd_file="$(mktemp "${TMPDIR:-/tmp}/gwm-cd.XXXXXX")" || return
+  GWM_CD_FILE="$__gwm_cd_file" command gwm "$@"
+  __gwm_status=$?
+  __gwm_dir="$(cat "$__gwm_cd_file")"
+  rm -f "$__gwm_cd_file"
+  if [ -n "$__gwm_dir" ] && [ -d "$__gwm_dir" ]; then
+    builtin cd -- "$__gwm_dir" || return
+  fi
+  return $__gwm_status
+}
+`
+
+const fishInit = `# gwm shell integration (gwm shell-init fish | source)
+function gwm
+    set -l __gwm_cd_file (mktemp)
+    or return 1
+    GWM_CD_FILE=$__gwm_cd_file command gwm $argv
+    set -l __gwm_status $status
+    set -l __gwm_dir (cat $__gwm_cd_file)
+    rm -f $__gwm_cd_file
+    if test -n "$__gwm_dir"; and test -d "$__gwm_dir"
+        builtin cd -- $__gwm_dir
+    end
+    return $__gwm_status
+end
+`
+
+// InitScript returns the wrapper function for the given shell.
+// The wrapper cds into the path written by CdLauncher after gwm exits.
+func InitScript(shell string) (string, error) {
+	switch shell {
+	case "bash", "zsh":
+		return fmt.Sprintf(posixInit, shell), nil
+	case "fish":
+		return fishInit, nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s (bash|zsh|fish)", shell)
+	}
+}
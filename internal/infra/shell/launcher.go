@@ -0,0 +1,57 @@
+package shell
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// CdFileEnv はシェルラッパーが移動先パスの受け渡しに使うファイル名を指す環境変数。
+const CdFileEnv = "GWM_CD_FILE"
+
+// CdLauncher implements domain.SessionLauncher by handing the worktree path
+// over to the shell wrapper emitted by `gwm shell-init`.
+type CdLauncher struct {
+	out    io.Writer
+	cdFile string
+}
+
+// NewCdLauncher writes the path to $GWM_CD_FILE when the wrapper set it,
+// otherwise to stdout.
+func NewCdLauncher() *CdLauncher {
+	return &CdLauncher{out: os.Stdout, cdFile: os.Getenv(CdFileEnv)}
+}
+
+// Launch moves the calling shell to the worktree. A detached launch fails
+// with domain.ErrDetachUnsupported since there is no session to start.
+func (l *CdLauncher) Launch(wt domain.WorktreeInfo, opts domain.LaunchOptions) error {
+	if opts.Detach {
+		return domain.ErrDetachUnsupported
+	}
+	path, err := AbsPath(wt)
+	if err != nil {
+		return err
+	}
+	if l.cdFile != "" {
+		return os.WriteFile(l.cdFile, []byte(path), 0o600)
+	}
+	_, err = fmt.Fprintln(l.out, path)
+	return err
+}
+
+// Kill は何もしない（シェル側にセッションを持たないため）。
+func (l *CdLauncher) Kill(domain.WorktreeInfo) error {
+	return nil
+}
+
+// HasSession は常に false（シェル側にセッションを持たないため）。
+func (l *CdLauncher) HasSession(domain.WorktreeInfo) (bool, error) {
+	return false, nil
+}
+
+// Windows は常に 0。
+func (l *CdLauncher) Windows(domain.WorktreeInfo) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,108 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestCdLauncherWritesCdFile(t *testing.T) {
+	dir := t.TempDir()
+	cdFile := filepath.Join(dir, "cd")
+	l := &CdLauncher{out: &bytes.Buffer{}, cdFile: cdFile}
+
+	if err := l.Launch(domain.WorktreeInfo{Branch: "feature/foo", Path: dir}, domain.LaunchOptions{}); err != nil {
+		t.Fatalf("Launch returned error: %v", err)
+	}
+	got, err := os.ReadFile(cdFile)
+	if err != nil {
+		t.Fatalf("read cd file: %v", err)
+	}
+	if string(got) != dir {
+		t.Fatalf("cd file = %q, want %q", got, dir)
+	}
+}
+
+func TestInitScript(t *testing.T) {
+	for _, sh := range []string{"bash", "zsh", "fish"} {
+		script, err := InitScript(sh)
+		if err != nil {
+			t.Fatalf("InitScript(%q) error: %v", sh, err)
+		}
+		if !strings.Contains(script, CdFileEnv) {
+			t.Fatalf("InitScript(%q) does not reference %s", sh, CdFileEnv)
+		}
+	}
+	if _, err := InitScript("tcsh"); err == nil {
+		t.Fatalf("expected error for unsupported shell")
+	}
+}
+
+func TestSpawnLauncherRunsShellInWorktree(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "pwd")
+	script := filepath.Join(dir, "fake-shell")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\npwd > "+out+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	l := &SpawnLauncher{shell: script}
+
+	if err := l.Launch(domain.WorktreeInfo{Path: dir}, domain.LaunchOptions{}); err != nil {
+		t.Fatalf("Launch returned error: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("shell did not run: %v", err)
+	}
+	if want, _ := filepath.EvalSymlinks(dir); strings.TrimSpace(string(got)) != want {
+		t.Fatalf("shell ran in %q, want %q", got, want)
+	}
+	if err := l.Launch(domain.WorktreeInfo{}, domain.LaunchOptions{}); err == nil {
+		t.Fatal("expected error for empty path")
+	}
+}
+
+func TestSpawnLauncherIgnoresShellExitStatus(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "fake-shell")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexit 3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// 最後のコマンドが失敗したシェルの終了は、起動の失敗ではない。
+	if err := (&SpawnLauncher{shell: script}).Launch(domain.WorktreeInfo{Path: dir}, domain.LaunchOptions{}); err != nil {
+		t.Fatalf("Launch returned error for a shell exiting 3: %v", err)
+	}
+	if err := (&SpawnLauncher{shell: filepath.Join(dir, "missing")}).Launch(domain.WorktreeInfo{Path: dir}, domain.LaunchOptions{}); err == nil {
+		t.Fatal("a shell that cannot start should be reported")
+	}
+}
+
+func TestLaunchersRejectDetach(t *testing.T) {
+	dir := t.TempDir()
+	cdFile := filepath.Join(dir, "cd")
+	ran := filepath.Join(dir, "ran")
+	script := filepath.Join(dir, "fake-shell")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ntouch "+ran+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wt := domain.WorktreeInfo{Path: dir}
+	opts := domain.LaunchOptions{Detach: true}
+
+	if err := (&CdLauncher{out: &bytes.Buffer{}, cdFile: cdFile}).Launch(wt, opts); !errors.Is(err, domain.ErrDetachUnsupported) {
+		t.Fatalf("CdLauncher detach = %v", err)
+	}
+	if err := (&SpawnLauncher{shell: script}).Launch(wt, opts); !errors.Is(err, domain.ErrDetachUnsupported) {
+		t.Fatalf("SpawnLauncher detach = %v", err)
+	}
+	for _, p := range []string{cdFile, ran} {
+		if _, err := os.Stat(p); err == nil {
+			t.Fatalf("detached launch should do nothing, found %s", p)
+		}
+	}
+}
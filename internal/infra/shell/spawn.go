@@ -0,0 +1,83 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// SpawnLauncher implements domain.SessionLauncher without a multiplexer by
+// starting an interactive $SHELL in the worktree; gwm returns when it exits.
+type SpawnLauncher struct {
+	shell string
+}
+
+// NewSpawnLauncher uses $SHELL, falling back to /bin/sh.
+func NewSpawnLauncher() *SpawnLauncher {
+	sh := os.Getenv("SHELL")
+	if strings.TrimSpace(sh) == "" {
+		sh = "/bin/sh"
+	}
+	return &SpawnLauncher{shell: sh}
+}
+
+// Launch runs the shell in the worktree. A detached launch fails with
+// domain.ErrDetachUnsupported because the shell cannot outlive gwm.
+func (l *SpawnLauncher) Launch(wt domain.WorktreeInfo, opts domain.LaunchOptions) error {
+	if opts.Detach {
+		return domain.ErrDetachUnsupported
+	}
+	path, err := AbsPath(wt)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s を開きます (exit で戻ります)\n", path)
+	return StartError(Interactive(path, l.shell).Run())
+}
+
+// Kill は何もしない（シェルは呼び出し元の端末で終了する）。
+func (l *SpawnLauncher) Kill(domain.WorktreeInfo) error {
+	return nil
+}
+
+// HasSession は常に false（残り続けるセッションを持たないため）。
+func (l *SpawnLauncher) HasSession(domain.WorktreeInfo) (bool, error) {
+	return false, nil
+}
+
+// Windows は常に 0。
+func (l *SpawnLauncher) Windows(domain.WorktreeInfo) (int, error) {
+	return 0, nil
+}
+
+// AbsPath returns the worktree's absolute path, rejecting an empty one.
+func AbsPath(wt domain.WorktreeInfo) (string, error) {
+	if strings.TrimSpace(wt.Path) == "" {
+		return "", errors.New("worktree path is empty")
+	}
+	return filepath.Abs(wt.Path)
+}
+
+// StartError drops the exit status of an interactive command that ran: a
+// shell or session ending with a failing last command is not a launch
+// failure. Errors starting the command are returned as is.
+func StartError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+	return err
+}
+
+// Interactive builds a command in dir that takes over the terminal.
+func Interactive(dir, name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd
+}
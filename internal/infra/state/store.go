@@ -0,0 +1,58 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// Store persists the allocation registry as JSON on local filesystem.
+type Store struct {
+	path string
+}
+
+// NewStore creates a Store rooted at repoDir/.gwm/state.json.
+func NewStore(repoDir string) *Store {
+	return &Store{path: filepath.Join(repoDir, ".gwm", "state.json")}
+}
+
+// Load reads the state. Empty file or missing file returns empty state.
+func (s *Store) Load() (domain.State, error) {
+	var st domain.State
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if len(data) == 0 {
+		return st, nil
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return domain.State{}, err
+	}
+	return st, nil
+}
+
+// Save writes the state atomically.
+func (s *Store) Save(st domain.State) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	if st.Allocations == nil {
+		st.Allocations = []domain.Allocation{}
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
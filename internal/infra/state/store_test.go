@@ -0,0 +1,34 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestStoreLoadSave(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	st, err := s.Load()
+	if err != nil {
+		t.Fatalf("load err: %v", err)
+	}
+	if len(st.Allocations) != 0 {
+		t.Fatalf("expected empty state, got %+v", st)
+	}
+
+	want := domain.State{Allocations: []domain.Allocation{
+		{Branch: "feature/foo", Path: "/tmp/wt/foo", Index: 1, Port: 3010, PortCount: 10},
+	}}
+	if err := s.Save(want); err != nil {
+		t.Fatalf("save err: %v", err)
+	}
+	got, err := s.Load()
+	if err != nil {
+		t.Fatalf("load err: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
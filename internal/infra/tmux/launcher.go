@@ -11,22 +11,33 @@ import (
 	gotmux "github.com/jubnzv/go-tmux"
 
 	"github.com/example/gwm/internal/domain"
+	"github.com/example/gwm/internal/infra/shell"
 )
 
 // Launcher implements domain.SessionLauncher using tmux with a shell fallback.
 type Launcher struct {
 	server         *gotmux.Server
 	useControlMode bool
+	// settings supplies the window layout for new sessions.
+	settings domain.Settings
+	// names chooses and remembers session names; nil uses gwm-<branch>.
+	names *domain.SessionNameService
 }
 
-func NewLauncher(settings domain.Settings) *Launcher {
+func NewLauncher(settings domain.Settings, names *domain.SessionNameService) *Launcher {
 	return &Launcher{
 		server:         gotmux.NewServer("", "", nil),
 		useControlMode: settings.TmuxControlMode,
+		settings:       settings,
+		names:          names,
 	}
 }
 
-func (l *Launcher) Launch(wt domain.WorktreeInfo) error {
+// Launch attaches to the worktree's session, creating it with the configured
+// layout first. Inside tmux the current client is switched instead, or the
+// worktree is opened in the current session for window/pane targets and
+// tmuxInside "window". opts.Detach only creates the session, window or pane.
+func (l *Launcher) Launch(wt domain.WorktreeInfo, opts domain.LaunchOptions) error {
 	if strings.TrimSpace(wt.Path) == "" {
 		return errors.New("worktree path is empty")
 	}
@@ -39,96 +50,161 @@ func (l *Launcher) Launch(wt domain.WorktreeInfo) error {
 		}
 	}
 
-	// tmux が無い場合は従来のシェル起動にフォールバック
+	// tmux が無い場合は worktree でシェルを起動する。
 	if !isTmuxAvailable() {
-		return errors.New("tmux が見つかりません")
+		fmt.Fprintln(os.Stderr, "tmux が見つからないため、シェルを起動します")
+		return shell.NewSpawnLauncher().Launch(wt, opts)
 	}
 
-	sessionName := firstNonEmpty(sessionNameCandidates(wt))
-	if sessionName == "" {
-		sessionName = "gwm-session"
+	target := opts.Target
+	if target == domain.TargetSession && gotmux.IsInsideTmux() && l.settings.TmuxInside == domain.TmuxInsideWindow {
+		target = domain.TargetWindow
+	}
+	if target != domain.TargetSession {
+		if !gotmux.IsInsideTmux() {
+			return fmt.Errorf("opening a worktree as a %s requires running inside tmux", target)
+		}
+		return openInCurrentSession(target, windowName(wt), path, opts.Detach)
 	}
 
-	has, err := l.server.HasSession(sessionName)
+	wt.Path = path
+	sessionName, err := l.names.Resolve(wt, func(name string) (bool, error) {
+		// 同じ名前のセッションが別のディレクトリで動いていれば他の worktree のもの。
+		return hasSession(name) && sessionPath(name) != path, nil
+	})
 	if err != nil {
-		// サーバーがまだ立ち上がっていない場合でも新規作成を試みる
-		printTmuxFailure(fmt.Sprintf("セッション確認に失敗しました (新規作成を試みます): %v", err))
+		return err
+	}
+	if sessionName == "" {
+		sessionName = "gwm-session"
 	}
 
-	var session gotmux.Session
-	if err == nil && has {
-		session = gotmux.Session{Name: sessionName}
-	} else {
-		session, err = l.server.NewSession(sessionName, "-c", path)
-		if err != nil {
+	if !hasSession(sessionName) {
+		if layout, ok := l.settings.LayoutFor(wt.Branch); ok && len(layout.Windows) > 0 {
+			if err := newSessionWithLayout(sessionName, path, layout); err != nil {
+				printTmuxFailure(fmt.Sprintf("レイアウトの作成に失敗しました: %v", err))
+				return err
+			}
+		} else if _, err := l.server.NewSession(sessionName, "-c", path); err != nil {
 			printTmuxFailure(fmt.Sprintf("セッション作成に失敗しました: %v", err))
 			return err
 		}
 	}
-
-	if err := l.attachSession(session); err != nil {
+	// セッションができてから名前を記録する。作成に失敗した名前は残さない。
+	if err := l.names.Remember(wt, sessionName); err != nil {
 		return err
 	}
-	return nil
+	if opts.Detach {
+		return nil
+	}
+	return l.attachSession(sessionName)
 }
 
-// Kill terminates a tmux session related to the worktree if it exists.
+// Kill terminates the worktree's tmux session if it exists.
 func (l *Launcher) Kill(wt domain.WorktreeInfo) error {
 	if !isTmuxAvailable() {
 		return nil
 	}
+	name := l.findSession(wt)
+	if name == "" {
+		return nil
+	}
+	if _, err := runTmux("kill-session", "-t", "="+name); err != nil {
+		printTmuxFailure(fmt.Sprintf("セッション削除に失敗しました: %v", err))
+		return err
+	}
+	return nil
+}
 
-	for _, name := range sessionNameCandidates(wt) {
-		if name == "" {
-			continue
-		}
-		has, err := l.server.HasSession(name)
-		if err != nil {
-			printTmuxFailure(fmt.Sprintf("セッション確認に失敗しました: %v", err))
-			continue
+// HasSession reports whether the worktree's tmux session exists.
+func (l *Launcher) HasSession(wt domain.WorktreeInfo) (bool, error) {
+	if !isTmuxAvailable() {
+		return false, nil
+	}
+	return l.findSession(wt) != "", nil
+}
+
+// Windows counts the windows of the worktree's session.
+func (l *Launcher) Windows(wt domain.WorktreeInfo) (int, error) {
+	if !isTmuxAvailable() {
+		return 0, nil
+	}
+	name := l.findSession(wt)
+	if name == "" {
+		return 0, nil
+	}
+	out, err := runTmux("list-windows", "-t", "="+name, "-F", "#{window_index}")
+	if err != nil {
+		return 0, err
+	}
+	return len(strings.Fields(out)), nil
+}
+
+// findSession returns the running session of wt: the stored name, or else a
+// gwm-<branch> or legacy name whose start directory is the worktree (so that
+// a same-named session of another repository is not mistaken for it).
+func (l *Launcher) findSession(wt domain.WorktreeInfo) string {
+	if name, ok, err := l.names.Lookup(wt); err == nil && ok {
+		if hasSession(name) {
+			return name
 		}
-		if !has {
-			continue
+		return ""
+	}
+	path, err := filepath.Abs(wt.Path)
+	if err != nil {
+		return ""
+	}
+	for _, name := range sessionNameCandidates(wt) {
+		if hasSession(name) && sessionPath(name) == path {
+			return name
 		}
-		if err := l.server.KillSession(name); err != nil {
-			printTmuxFailure(fmt.Sprintf("セッション削除に失敗しました: %v", err))
-			return err
+	}
+	return ""
+}
+
+// runTmux runs a tmux command and returns its trimmed stdout. Tests replace it.
+var runTmux = func(args ...string) (string, error) {
+	out, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("tmux %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
 		}
+		return "", fmt.Errorf("tmux %s: %w", args[0], err)
 	}
-	return nil
+	return strings.TrimSpace(string(out)), nil
 }
 
-func isTmuxAvailable() bool {
-	_, err := exec.LookPath("tmux")
+// hasSession checks for a session named exactly name; a plain -t target
+// would also match sessions that merely start with name. A server that is
+// not running counts as no session.
+func hasSession(name string) bool {
+	_, err := runTmux("has-session", "-t", "="+name)
 	return err == nil
 }
 
-func sanitizeSessionName(name string) string {
-	s := strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
-			(r >= '0' && r <= '9') {
-			return r
-		}
-		if r == '-' || r == '_' {
-			return r
-		}
-		if r == '/' || r == '\\' {
-			return '-'
-		}
-		return -1
-	}, name)
-	return strings.Trim(s, "-_")
+// sessionPath returns the start directory of the session.
+func sessionPath(name string) string {
+	out, err := runTmux("display-message", "-p", "-t", "="+name+":", "#{session_path}")
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+func isTmuxAvailable() bool {
+	_, err := exec.LookPath("tmux")
+	return err == nil
 }
 
 func sessionNameCandidates(wt domain.WorktreeInfo) []string {
-	branch := sanitizeSessionName(wt.Branch)
-	trimmedBranch := sanitizeSessionName(strings.TrimPrefix(wt.Branch, "refs/heads/"))
-	base := sanitizeSessionName(filepath.Base(wt.Path))
+	branch := domain.SanitizeSessionName(wt.Branch)
+	trimmedBranch := domain.SanitizeSessionName(strings.TrimPrefix(wt.Branch, "refs/heads/"))
+	base := domain.SanitizeSessionName(filepath.Base(wt.Path))
 
-	primary := firstNonEmpty([]string{trimmedBranch, branch, base})
 	var candidates []string
-	if primary != "" {
-		candidates = append(candidates, "gwm-"+primary)
+	if primary := domain.SessionName(wt); primary != "" {
+		candidates = append(candidates, primary)
 	}
 
 	// Legacy names (without prefix) are kept for cleanup/compatibility.
@@ -166,9 +242,58 @@ func printTmuxFailure(message string) {
 	fmt.Fprintf(os.Stderr, "tmux の起動に失敗しました: %s\n", message)
 }
 
-func (l *Launcher) attachSession(session gotmux.Session) error {
+// openInCurrentSession opens path as a window (reusing one with the same
+// name) or as a pane split off the current window. detach keeps the focus
+// where it is.
+func openInCurrentSession(target domain.LaunchTarget, name, path string, detach bool) error {
+	var flags []string
+	if detach {
+		flags = []string{"-d"}
+	}
+	if target == domain.TargetPane {
+		_, err := runTmux(append(append([]string{"split-window"}, flags...), "-h", "-c", path)...)
+		return err
+	}
+	out, err := runTmux("list-windows", "-F", "#{window_id} #{window_name}")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if id, n, ok := strings.Cut(line, " "); ok && n == name {
+			if detach {
+				return nil
+			}
+			_, err := runTmux("select-window", "-t", id)
+			return err
+		}
+	}
+	_, err = runTmux(append(append([]string{"new-window"}, flags...), "-n", name, "-c", path)...)
+	return err
+}
+
+// windowName is the session name without the gwm- prefix.
+func windowName(wt domain.WorktreeInfo) string {
+	name := strings.TrimPrefix(firstNonEmpty(sessionNameCandidates(wt)), "gwm-")
+	if name == "" {
+		return "gwm"
+	}
+	return name
+}
+
+func (l *Launcher) attachSession(name string) error {
+	// 前方一致しないよう "=" を付けて完全一致で指定する。
+	session := gotmux.Session{Name: "=" + name}
+	// tmux の中から attach すると入れ子になるので、現在のクライアントを切り替える。
+	if gotmux.IsInsideTmux() {
+		if _, err := runTmux("switch-client", "-t", session.Name); err != nil {
+			printTmuxFailure(fmt.Sprintf("セッションの切り替えに失敗しました: %v", err))
+			return err
+		}
+		return nil
+	}
+
 	// -CC での起動は tmux 外から制御モードで接続したい場合のみ使う。
-	if l.useControlMode && !gotmux.IsInsideTmux() {
+	if l.useControlMode {
 		args := []string{"-CC", "attach-session", "-t", session.Name}
 		if err := gotmux.ExecCmd(args); err != nil {
 			printTmuxFailure(fmt.Sprintf("セッションへの接続に失敗しました: %v", err))
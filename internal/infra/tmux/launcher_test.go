@@ -1,22 +1,221 @@
 package tmux
 
-import "testing"
-
-func TestSanitizeSessionName(t *testing.T) {
-	cases := []struct {
-		in   string
-		want string
-	}{
-		{"feature/foo", "feature-foo"},
-		{"bugfix_123", "bugfix_123"},
-		{"../weird", "weird"},
-		{"ABC:def", "ABCdef"},
-		{"", ""},
-	}
-
-	for _, c := range cases {
-		if got := sanitizeSessionName(c.in); got != c.want {
-			t.Fatalf("sanitizeSessionName(%q) = %q, want %q", c.in, got, c.want)
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestNewSessionWithLayout(t *testing.T) {
+	var calls []string
+	orig := runTmux
+	defer func() { runTmux = orig }()
+	runTmux = func(args ...string) (string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "new-session":
+			return "@1 %1", nil
+		case "new-window":
+			return "@2 %2", nil
+		case "split-window":
+			return "%3", nil
+		}
+		return "", nil
+	}
+
+	layout := domain.SessionLayout{Windows: []domain.LayoutWindow{
+		{Name: "editor", Command: "nvim"},
+		{Name: "dev", Dir: "web", Command: "npm run dev", Layout: "main-horizontal",
+			Panes: []domain.LayoutPane{{Split: domain.SplitVertical, Size: 30, Command: "go test ./..."}}},
+	}}
+	if err := newSessionWithLayout("gwm-foo", "/wt", layout); err != nil {
+		t.Fatalf("newSessionWithLayout returned error: %v", err)
+	}
+
+	want := []string{
+		"new-session -d -s gwm-foo -c /wt -P -F #{window_id} #{pane_id} -n editor",
+		"send-keys -t %1 -l nvim",
+		"send-keys -t %1 Enter",
+		"new-window -d -t =gwm-foo: -c /wt/web -P -F #{window_id} #{pane_id} -n dev",
+		"send-keys -t %2 -l npm run dev",
+		"send-keys -t %2 Enter",
+		"split-window -t @2 -c /wt/web -P -F #{pane_id} -v -l 30%",
+		"send-keys -t %3 -l go test ./...",
+		"send-keys -t %3 Enter",
+		"select-layout -t @2 main-horizontal",
+		"select-pane -t %2",
+		"select-window -t @1",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("tmux calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestNewSessionWithLayoutKillsHalfBuiltSession(t *testing.T) {
+	var killed bool
+	orig := runTmux
+	defer func() { runTmux = orig }()
+	runTmux = func(args ...string) (string, error) {
+		switch args[0] {
+		case "new-session":
+			return "@1 %1", nil
+		case "new-window":
+			return "", errors.New("boom")
+		case "kill-session":
+			killed = true
+		}
+		return "", nil
+	}
+
+	layout := domain.SessionLayout{Windows: []domain.LayoutWindow{{Name: "a"}, {Name: "b"}}}
+	if err := newSessionWithLayout("gwm-foo", "/wt", layout); err == nil || !killed {
+		t.Fatalf("err=%v killed=%v", err, killed)
+	}
+}
+
+func TestOpenInCurrentSession(t *testing.T) {
+	var calls []string
+	orig := runTmux
+	defer func() { runTmux = orig }()
+	runTmux = func(args ...string) (string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[0] == "list-windows" {
+			return "@1 editor\n@4 feature-foo", nil
+		}
+		return "", nil
+	}
+
+	if err := openInCurrentSession(domain.TargetWindow, "feature-foo", "/wt/foo", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := openInCurrentSession(domain.TargetWindow, "fix-bar", "/wt/bar", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := openInCurrentSession(domain.TargetPane, "fix-bar", "/wt/bar", false); err != nil {
+		t.Fatal(err)
+	}
+	// detach では既存ウィンドウを選択せず、新しいものも -d で作る。
+	if err := openInCurrentSession(domain.TargetWindow, "feature-foo", "/wt/foo", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := openInCurrentSession(domain.TargetWindow, "fix-bar", "/wt/bar", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := openInCurrentSession(domain.TargetPane, "fix-bar", "/wt/bar", true); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"list-windows -F #{window_id} #{window_name}",
+		"select-window -t @4",
+		"list-windows -F #{window_id} #{window_name}",
+		"new-window -n fix-bar -c /wt/bar",
+		"split-window -h -c /wt/bar",
+		"list-windows -F #{window_id} #{window_name}",
+		"list-windows -F #{window_id} #{window_name}",
+		"new-window -d -n fix-bar -c /wt/bar",
+		"split-window -d -h -c /wt/bar",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("tmux calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLaunchOutsideTmuxRejectsWindowTarget(t *testing.T) {
+	if !isTmuxAvailable() {
+		t.Skip("tmux not installed")
+	}
+	t.Setenv("TMUX", "")
+	l := NewLauncher(domain.Settings{}, nil)
+	err := l.Launch(domain.WorktreeInfo{Branch: "feature/foo", Path: t.TempDir()}, domain.LaunchOptions{Target: domain.TargetPane})
+	if err == nil || !strings.Contains(err.Error(), "inside tmux") {
+		t.Fatalf("Launch = %v, want inside tmux error", err)
+	}
+}
+
+type memoryState struct{ st domain.State }
+
+func (m *memoryState) Load() (domain.State, error) { return m.st, nil }
+func (m *memoryState) Save(st domain.State) error  { m.st = st; return nil }
+
+func TestFindSessionUsesStoredNameAndChecksOwnership(t *testing.T) {
+	sessions := map[string]string{
+		"gwm-main":        "/other/repo",
+		"gwm-feat-ab":     "/repo/worktrees/feat/ab",
+		"gwm-feat-ab-1f2": "/repo/worktrees/feat/a.b",
+	}
+	orig := runTmux
+	defer func() { runTmux = orig }()
+	runTmux = func(args ...string) (string, error) {
+		target := strings.TrimSuffix(strings.TrimPrefix(args[len(args)-1], "="), ":")
+		if args[0] == "display-message" {
+			target = strings.TrimSuffix(strings.TrimPrefix(args[3], "="), ":")
 		}
+		path, ok := sessions[target]
+		if !ok {
+			return "", errors.New("can't find session")
+		}
+		return path, nil
+	}
+
+	repo := &memoryState{st: domain.State{Sessions: []domain.SessionRecord{
+		{Branch: "feat/a.b", Path: "/repo/worktrees/feat/a.b", Name: "gwm-feat-ab-1f2"},
+	}}}
+	l := &Launcher{names: domain.NewSessionNameService(repo, domain.Settings{}, "/repo")}
+
+	if got := l.findSession(domain.WorktreeInfo{Branch: "feat/a.b", Path: "/repo/worktrees/feat/a.b"}); got != "gwm-feat-ab-1f2" {
+		t.Fatalf("stored name: got %q", got)
+	}
+	if got := l.findSession(domain.WorktreeInfo{Branch: "feat/ab", Path: "/repo/worktrees/feat/ab"}); got != "gwm-feat-ab" {
+		t.Fatalf("legacy name: got %q", got)
+	}
+	// 別リポジトリの gwm-main は自分のものとみなさない。
+	if got := l.findSession(domain.WorktreeInfo{Branch: "main", Path: "/repo"}); got != "" {
+		t.Fatalf("foreign session matched: %q", got)
+	}
+}
+
+func TestParseSessions(t *testing.T) {
+	out := "gwm-a:1:3:1700000000:/tmp/a\nwork:0:1:1700000100:/home/c:d\nbroken line"
+	got := parseSessions(out)
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	if got[0].Name != "gwm-a" || got[0].Path != "/tmp/a" || !got[0].Attached || got[0].Windows != 3 || got[0].Created.Unix() != 1700000000 {
+		t.Fatalf("unexpected first session: %+v", got[0])
+	}
+	if got[1].Attached || got[1].Path != "/home/c:d" {
+		t.Fatalf("unexpected second session: %+v", got[1])
+	}
+}
+
+func TestParseSessionsFromTmux(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available")
+	}
+	// 実際の tmux の -F 出力を読む。利用者のサーバーに触れないよう専用のソケットを使う。
+	socket := "gwm-test-" + strconv.Itoa(os.Getpid())
+	tmux := func(args ...string) (string, error) {
+		out, err := exec.Command("tmux", append([]string{"-L", socket, "-f", "/dev/null"}, args...)...).CombinedOutput()
+		return string(out), err
+	}
+	defer tmux("kill-server")
+	dir := t.TempDir()
+	if out, err := tmux("new-session", "-d", "-s", "gwm-feat-x", "-c", dir); err != nil {
+		t.Skipf("cannot start tmux: %v (%s)", err, out)
+	}
+	out, err := tmux("list-sessions", "-F", sessionListFormat)
+	if err != nil {
+		t.Fatalf("list-sessions: %v (%s)", err, out)
+	}
+
+	got := parseSessions(strings.TrimSpace(out))
+	if len(got) != 1 || got[0].Name != "gwm-feat-x" || got[0].Path != dir || got[0].Windows != 1 || got[0].Created.IsZero() {
+		t.Fatalf("parseSessions(%q) = %+v", out, got)
 	}
 }
@@ -0,0 +1,112 @@
+package tmux
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// newSessionWithLayout creates the detached session name rooted at root and
+// builds every window of layout in it. A half-built session is killed on
+// failure so that the next Launch starts over.
+func newSessionWithLayout(name, root string, layout domain.SessionLayout) error {
+	var first string
+	created := false
+	for i, w := range layout.Windows {
+		var args []string
+		if i == 0 {
+			args = []string{"new-session", "-d", "-s", name}
+		} else {
+			args = []string{"new-window", "-d", "-t", "=" + name + ":"}
+		}
+		args = append(args, "-c", layoutDir(root, w.Dir), "-P", "-F", "#{window_id} #{pane_id}")
+		if w.Name != "" {
+			args = append(args, "-n", w.Name)
+		}
+		out, err := runTmux(args...)
+		if err == nil {
+			created = true
+			var window string
+			window, err = buildWindow(out, root, w)
+			if i == 0 {
+				first = window
+			}
+		}
+		if err != nil {
+			if created {
+				runTmux("kill-session", "-t", "="+name)
+			}
+			return err
+		}
+	}
+	_, err := runTmux("select-window", "-t", first)
+	return err
+}
+
+// buildWindow splits the panes of a window created with the
+// "#{window_id} #{pane_id}" format and starts their commands.
+func buildWindow(ids, root string, w domain.LayoutWindow) (string, error) {
+	window, pane, ok := strings.Cut(ids, " ")
+	if !ok {
+		return "", fmt.Errorf("unexpected tmux output: %q", ids)
+	}
+	if err := sendCommand(pane, w.Command); err != nil {
+		return window, err
+	}
+	for _, p := range w.Panes {
+		dir := p.Dir
+		if dir == "" {
+			dir = w.Dir
+		}
+		args := []string{"split-window", "-t", window, "-c", layoutDir(root, dir), "-P", "-F", "#{pane_id}"}
+		if p.Split == domain.SplitVertical {
+			args = append(args, "-v")
+		} else {
+			args = append(args, "-h")
+		}
+		if p.Size > 0 {
+			args = append(args, "-l", fmt.Sprintf("%d%%", p.Size))
+		}
+		id, err := runTmux(args...)
+		if err != nil {
+			return window, err
+		}
+		if err := sendCommand(id, p.Command); err != nil {
+			return window, err
+		}
+	}
+	if w.Layout != "" {
+		if _, err := runTmux("select-layout", "-t", window, w.Layout); err != nil {
+			return window, err
+		}
+	}
+	if len(w.Panes) > 0 {
+		// 分割後は最後のペインが選ばれているので最初のペインに戻す。
+		if _, err := runTmux("select-pane", "-t", pane); err != nil {
+			return window, err
+		}
+	}
+	return window, nil
+}
+
+// sendCommand types command into pane and presses Enter, so the shell stays
+// open after the command exits.
+func sendCommand(pane, command string) error {
+	if strings.TrimSpace(command) == "" {
+		return nil
+	}
+	if _, err := runTmux("send-keys", "-t", pane, "-l", command); err != nil {
+		return err
+	}
+	_, err := runTmux("send-keys", "-t", pane, "Enter")
+	return err
+}
+
+func layoutDir(root, dir string) string {
+	if dir == "" {
+		return root
+	}
+	return filepath.Join(root, dir)
+}
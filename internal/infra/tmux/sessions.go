@@ -0,0 +1,68 @@
+package tmux
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+// sessionListFormat separates fields with ":", which tmux prints as is
+// (some versions replace tabs with "_") and never allows in session names.
+// The path comes last because it may contain ":" itself.
+const sessionListFormat = "#{session_name}:#{session_attached}:#{session_windows}:#{session_created}:#{session_path}"
+
+// Sessions lists every session of the tmux server, including ones gwm did
+// not create. A server that is not running has no sessions.
+func (l *Launcher) Sessions() ([]domain.SessionInfo, error) {
+	if !isTmuxAvailable() {
+		return nil, nil
+	}
+	out, err := runTmux("list-sessions", "-F", sessionListFormat)
+	if err != nil {
+		if noServer(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseSessions(out), nil
+}
+
+// KillSession kills the session called exactly name.
+func (l *Launcher) KillSession(name string) error {
+	_, err := runTmux("kill-session", "-t", "="+name)
+	return err
+}
+
+// AttachSession attaches to (or switches the client to) the named session.
+func (l *Launcher) AttachSession(name string) error {
+	return l.attachSession(name)
+}
+
+// noServer reports whether err only says that no tmux server is running.
+func noServer(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "no server running") || strings.Contains(msg, "error connecting to")
+}
+
+func parseSessions(out string) []domain.SessionInfo {
+	var list []domain.SessionInfo
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.SplitN(line, ":", 5)
+		if len(f) != 5 || f[0] == "" {
+			continue
+		}
+		s := domain.SessionInfo{Name: f[0], Path: f[4]}
+		// session_attached は接続中のクライアント数。
+		if n, err := strconv.Atoi(f[1]); err == nil {
+			s.Attached = n > 0
+		}
+		s.Windows, _ = strconv.Atoi(f[2])
+		if sec, err := strconv.ParseInt(f[3], 10, 64); err == nil {
+			s.Created = time.Unix(sec, 0)
+		}
+		list = append(list, s)
+	}
+	return list
+}
@@ -0,0 +1,132 @@
+package zellij
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/example/gwm/internal/domain"
+	"github.com/example/gwm/internal/infra/shell"
+)
+
+// Launcher implements domain.SessionLauncher with zellij sessions named like
+// the tmux ones (gwm-<branch>).
+type Launcher struct {
+	// run executes a non-interactive zellij command and returns its stdout.
+	run func(args ...string) (string, error)
+	// attach runs zellij in dir on the current terminal.
+	attach func(dir string, args ...string) error
+	// inside reports whether gwm runs inside a zellij session.
+	inside func() bool
+}
+
+func NewLauncher() *Launcher {
+	return &Launcher{
+		run: func(args ...string) (string, error) {
+			out, err := exec.Command("zellij", args...).Output()
+			if err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+					return "", fmt.Errorf("zellij %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+				}
+				return "", fmt.Errorf("zellij %s: %w", args[0], err)
+			}
+			return string(out), nil
+		},
+		attach: func(dir string, args ...string) error {
+			return shell.Interactive(dir, "zellij", args...).Run()
+		},
+		inside: func() bool { return os.Getenv("ZELLIJ") != "" },
+	}
+}
+
+// Launch attaches to (or creates) the worktree's session. zellij cannot nest
+// or switch sessions from the command line, so inside zellij the worktree
+// opens as a tab, or as a pane for TargetPane. opts.Detach creates the
+// session in the background, also from inside zellij.
+func (l *Launcher) Launch(wt domain.WorktreeInfo, opts domain.LaunchOptions) error {
+	path, err := shell.AbsPath(wt)
+	if err != nil {
+		return err
+	}
+	name := sessionName(wt)
+	if opts.Detach {
+		if opts.Target != domain.TargetSession {
+			return fmt.Errorf("zellij cannot open a %s in the background", opts.Target)
+		}
+		return l.attach(path, "attach", "--create-background", name)
+	}
+	if !l.inside() {
+		if opts.Target != domain.TargetSession {
+			return fmt.Errorf("opening a worktree as a %s requires running inside zellij", opts.Target)
+		}
+		return shell.StartError(l.attach(path, "attach", "--create", name))
+	}
+	if opts.Target == domain.TargetPane {
+		_, err = l.run("action", "new-pane", "--cwd", path)
+		return err
+	}
+	_, err = l.run("action", "new-tab", "--name", strings.TrimPrefix(name, "gwm-"), "--cwd", path)
+	return err
+}
+
+// Kill ends the worktree's session and drops it from the resurrectable list.
+func (l *Launcher) Kill(wt domain.WorktreeInfo) error {
+	has, err := l.HasSession(wt)
+	if err != nil || !has {
+		return err
+	}
+	name := sessionName(wt)
+	if _, err := l.run("kill-session", name); err != nil {
+		return err
+	}
+	// 終了済みセッションとして残り、attach で復活してしまうので削除する。
+	if _, err := l.run("delete-session", name); err != nil {
+		return fmt.Errorf("session %s was killed but could not be deleted: %w", name, err)
+	}
+	return nil
+}
+
+// HasSession reports whether the worktree's session is listed, including
+// exited sessions that `zellij attach` would resurrect.
+func (l *Launcher) HasSession(wt domain.WorktreeInfo) (bool, error) {
+	out, err := l.run("list-sessions", "--short", "--no-formatting")
+	if err != nil {
+		// セッションが 1 つも無いときもエラー終了する。
+		return false, nil
+	}
+	name := sessionName(wt)
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Windows counts the tabs of the worktree's session.
+func (l *Launcher) Windows(wt domain.WorktreeInfo) (int, error) {
+	if has, err := l.HasSession(wt); err != nil || !has {
+		return 0, err
+	}
+	out, err := l.run("--session", sessionName(wt), "action", "query-tab-names")
+	if err != nil {
+		return 0, nil
+	}
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func sessionName(wt domain.WorktreeInfo) string {
+	if name := domain.SessionName(wt); name != "" {
+		return name
+	}
+	return "gwm-session"
+}
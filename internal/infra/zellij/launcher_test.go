@@ -0,0 +1,128 @@
+package zellij
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+type fakeZellij struct {
+	sessions string
+	calls    []string
+	attached []string
+	inside   bool
+	// failDelete makes delete-session fail.
+	failDelete bool
+}
+
+func (f *fakeZellij) launcher() *Launcher {
+	return &Launcher{
+		run: func(args ...string) (string, error) {
+			f.calls = append(f.calls, strings.Join(args, " "))
+			switch args[0] {
+			case "list-sessions":
+				if f.sessions == "" {
+					return "", errors.New("No active zellij sessions found.")
+				}
+				return f.sessions, nil
+			case "--session":
+				return "editor\ndev server\n", nil
+			case "delete-session":
+				if f.failDelete {
+					return "", errors.New("session is still running")
+				}
+			}
+			return "", nil
+		},
+		attach: func(dir string, args ...string) error {
+			f.attached = append(f.attached, dir+": "+strings.Join(args, " "))
+			return nil
+		},
+		inside: func() bool { return f.inside },
+	}
+}
+
+func TestLauncherLaunch(t *testing.T) {
+	f := &fakeZellij{}
+	l := f.launcher()
+	wt := domain.WorktreeInfo{Branch: "refs/heads/feature/foo", Path: "/wt/foo"}
+
+	if err := l.Launch(wt, domain.LaunchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/wt/foo: attach --create gwm-feature-foo"}; !reflect.DeepEqual(f.attached, want) {
+		t.Fatalf("attached = %q", f.attached)
+	}
+	if err := l.Launch(wt, domain.LaunchOptions{Target: domain.TargetPane}); err == nil {
+		t.Fatal("pane target outside zellij should fail")
+	}
+
+	f.inside = true
+	if err := l.Launch(wt, domain.LaunchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Launch(wt, domain.LaunchOptions{Target: domain.TargetPane}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"action new-tab --name feature-foo --cwd /wt/foo", "action new-pane --cwd /wt/foo"}
+	if !reflect.DeepEqual(f.calls, want) || len(f.attached) != 1 {
+		t.Fatalf("calls = %q attached = %q", f.calls, f.attached)
+	}
+}
+
+func TestLauncherSessions(t *testing.T) {
+	f := &fakeZellij{}
+	l := f.launcher()
+	wt := domain.WorktreeInfo{Branch: "feature/foo", Path: "/wt/foo"}
+
+	if has, err := l.HasSession(wt); err != nil || has {
+		t.Fatalf("HasSession without sessions = %v, %v", has, err)
+	}
+	if err := l.Kill(wt); err != nil {
+		t.Fatal(err)
+	}
+
+	f.sessions = "gwm-feature-foobar\ngwm-feature-foo\n"
+	f.calls = nil
+	if n, err := l.Windows(wt); err != nil || n != 2 {
+		t.Fatalf("Windows = %d, %v", n, err)
+	}
+	if err := l.Kill(wt); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"list-sessions --short --no-formatting",
+		"--session gwm-feature-foo action query-tab-names",
+		"list-sessions --short --no-formatting",
+		"kill-session gwm-feature-foo",
+		"delete-session gwm-feature-foo",
+	}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("calls:\n%s", strings.Join(f.calls, "\n"))
+	}
+
+	// 削除できなければ attach で復活するので、Kill の失敗として返す。
+	f.failDelete = true
+	if err := l.Kill(wt); err == nil {
+		t.Fatal("a failing delete-session should be reported")
+	}
+}
+
+func TestLauncherDetachCreatesBackgroundSession(t *testing.T) {
+	f := &fakeZellij{inside: true}
+	l := f.launcher()
+	wt := domain.WorktreeInfo{Branch: "feature/foo", Path: "/wt/foo"}
+
+	if err := l.Launch(wt, domain.LaunchOptions{Detach: true}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/wt/foo: attach --create-background gwm-feature-foo"}; !reflect.DeepEqual(f.attached, want) || len(f.calls) != 0 {
+		t.Fatalf("attached = %q calls = %q", f.attached, f.calls)
+	}
+	if err := l.Launch(wt, domain.LaunchOptions{Target: domain.TargetWindow, Detach: true}); err == nil {
+		t.Fatal("a detached tab should be rejected")
+	}
+}
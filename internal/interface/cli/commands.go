@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -17,15 +18,39 @@ type App struct {
 	Config *usecase.ConfigInteractor
 	Cd     *usecase.CdInteractor
 	Remove *usecase.RemoveInteractor
-	Select func([]domain.WorktreeInfo) (domain.WorktreeInfo, error)
+	Rename *usecase.RenameInteractor
+	Env    *usecase.EnvInteractor
+	List   *usecase.ListInteractor
+	Prune  *usecase.PruneInteractor
+	// Restore recreates worktrees archived by `gwm remove --archive`.
+	Restore *usecase.RestoreInteractor
+	// Select lets the user pick a worktree; query pre-fills the fuzzy filter.
+	Select func(list []domain.WorktreeInfo, query string) (domain.WorktreeInfo, error)
+	// SelectMany lets the user pick several worktrees for bulk commands;
+	// when nil they fall back to Select.
+	SelectMany func(list []domain.WorktreeInfo, query string) ([]domain.WorktreeInfo, error)
+	// Session kills worktree sessions for `gwm kill-session` and lists them
+	// for `gwm sessions`.
+	Session *usecase.SessionInteractor
+	// ShellInit returns the wrapper script for `gwm shell-init <shell>`.
+	ShellInit func(shell string) (string, error)
+	// Confirm asks a yes/no question; nil reads the answer from stdin.
+	Confirm func(prompt string) bool
+	// UI runs the full-screen dashboard (`gwm` without arguments or `gwm ui`).
+	UI func() error
 }
 
 func (a *App) Run(args []string) int {
 	if len(args) < 1 {
-		fmt.Println("usage: gwm <command>")
+		if a.UI != nil {
+			return a.runUI(nil)
+		}
+		fmt.Println("usage: gwm [-C <dir>] <command>")
 		return 1
 	}
 	switch args[0] {
+	case "ui":
+		return a.runUI(args[1:])
 	case "create":
 		return a.runCreate(args[1:])
 	case "config":
@@ -34,6 +59,22 @@ func (a *App) Run(args []string) int {
 		return a.runCd(args[1:])
 	case "remove":
 		return a.runRemove(args[1:])
+	case "rename":
+		return a.runRename(args[1:])
+	case "prune":
+		return a.runPrune(args[1:])
+	case "restore":
+		return a.runRestore(args[1:])
+	case "kill-session":
+		return a.runKillSession(args[1:])
+	case "sessions":
+		return a.runSessions(args[1:])
+	case "list":
+		return a.runList(args[1:])
+	case "env":
+		return a.runEnv(args[1:])
+	case "shell-init":
+		return a.runShellInit(args[1:])
 	default:
 		fmt.Println("unknown command:", args[0])
 		return 1
@@ -43,22 +84,34 @@ func (a *App) Run(args []string) int {
 func (a *App) runCreate(args []string) int {
 	fs := flag.NewFlagSet("create", flag.ContinueOnError)
 	fs.SetOutput(os.Stdout)
-	if err := fs.Parse(args); err != nil {
+	var base string
+	fs.StringVar(&base, "base", "", "ref to branch from (branch, tag, commit, origin/<branch>)")
+	fs.StringVar(&base, "from", "", "alias of --base")
+	track := fs.Bool("track", false, "set the base remote branch as upstream")
+	keep := fs.Bool("keep-on-failure", false, "do not roll back the branch/worktree when a step fails")
+	noSession := fs.Bool("no-session", false, "do not launch a session")
+	detach := fs.Bool("detach", false, "start the session in the background without attaching")
+	if err := fs.Parse(reorderCreateArgs(args)); err != nil {
 		return 1
 	}
-	if fs.NArg() < 1 {
-		fmt.Println("usage: gwm create <branch>")
+	if fs.NArg() < 1 || (*noSession && *detach) {
+		fmt.Println("usage: gwm create <branch> [--base <ref>] [--track] [--keep-on-failure] [--no-session | --detach]")
 		return 1
 	}
 	branch := fs.Arg(0)
-	out, err := a.Create.Execute(usecase.CreateInput{Branch: branch})
+	in := usecase.CreateInput{Branch: branch, Base: base, Track: *track, KeepOnFailure: *keep, NoSession: *noSession, Detach: *detach}
+	out, err := a.Create.Execute(in)
+	for _, m := range out.Messages {
+		fmt.Println(m)
+	}
 	if err != nil {
 		fmt.Println("error:", err)
+		// --keep-on-failure で残した worktree の場所を示す。
+		if out.Worktree != "" {
+			fmt.Println("worktree:", out.Worktree)
+		}
 		return 1
 	}
-	for _, m := range out.Messages {
-		fmt.Println(m)
-	}
 	fmt.Println("worktree:", out.Worktree)
 	return 0
 }
@@ -83,15 +136,17 @@ func (a *App) runConfig(args []string) int {
 
 func (a *App) runConfigAdd(args []string) int {
 	fs := flag.NewFlagSet("config add", flag.ContinueOnError)
-	mode := fs.String("mode", "copy", "copy|symlink")
+	mode := fs.String("mode", "copy", "copy|symlink|template")
+	var exclude stringList
+	fs.Var(&exclude, "exclude", "pattern to skip when path is a glob (repeatable)")
 	if err := fs.Parse(reorderConfigAddArgs(args)); err != nil {
 		return 1
 	}
 	if fs.NArg() < 1 {
-		fmt.Println("usage: gwm config add <path> --mode copy|symlink")
+		fmt.Println("usage: gwm config add <path|pattern> --mode copy|symlink|template [--exclude <pattern>]...")
 		return 1
 	}
-	entry := domain.ConfigEntry{Path: fs.Arg(0), Mode: domain.Mode(*mode)}
+	entry := domain.ConfigEntry{Path: fs.Arg(0), Mode: domain.Mode(*mode), Exclude: exclude}
 	if err := a.Config.Add(entry); err != nil {
 		fmt.Println("error:", err)
 		return 1
@@ -101,20 +156,35 @@ func (a *App) runConfigAdd(args []string) int {
 }
 
 func (a *App) runConfigList(args []string) int {
-	if len(args) > 0 {
-		fmt.Println("usage: gwm config list")
+	fs := flag.NewFlagSet("config list", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	expand := fs.Bool("expand", false, "show the paths each entry currently expands to")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		fmt.Println("usage: gwm config list [--expand]")
 		return 1
 	}
-	entries, err := a.Config.List()
-	if err != nil {
-		fmt.Println("error:", err)
-		return 1
+	var v any
+	var n int
+	if *expand {
+		items, err := a.Config.ListExpanded()
+		if err != nil {
+			fmt.Println("error:", err)
+			return 1
+		}
+		v, n = items, len(items)
+	} else {
+		entries, err := a.Config.List()
+		if err != nil {
+			fmt.Println("error:", err)
+			return 1
+		}
+		v, n = entries, len(entries)
 	}
-	if len(entries) == 0 {
+	if n == 0 {
 		fmt.Println("no entries")
 		return 0
 	}
-	data, _ := json.MarshalIndent(entries, "", "  ")
+	data, _ := json.MarshalIndent(v, "", "  ")
 	fmt.Println(string(data))
 	return 0
 }
@@ -133,34 +203,162 @@ func (a *App) runConfigRemove(args []string) int {
 }
 
 func (a *App) runCd(args []string) int {
-	if len(args) != 0 {
-		fmt.Println("usage: gwm cd")
+	fs := flag.NewFlagSet("cd", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	printPath := fs.Bool("print-path", false, "print the selected worktree path to stdout instead of launching")
+	newWindow := fs.Bool("new-window", false, "open the worktree as a window of the current tmux session")
+	split := fs.Bool("split", false, "open the worktree in a new pane of the current tmux window")
+	noSession := fs.Bool("no-session", false, "run the on-enter hooks and print the path without launching a session")
+	detach := fs.Bool("detach", false, "start the session (or window/pane) in the background without attaching")
+	if err := fs.Parse(reorderCdArgs(args)); err != nil {
+		return 1
+	}
+	// --print-path と --no-session はセッションを開かないので、開き方のフラグとは併用できない。
+	launchFlags := *detach || *newWindow || *split
+	if fs.NArg() > 1 || (*newWindow && *split) || ((*printPath || *noSession) && launchFlags) || (*printPath && *noSession) {
+		fmt.Println("usage: gwm cd [<query>] [--print-path | --no-session | [--new-window | --split] [--detach]]")
+		return 1
+	}
+	opts := domain.LaunchOptions{Detach: *detach}
+	switch {
+	case *newWindow:
+		opts.Target = domain.TargetWindow
+	case *split:
+		opts.Target = domain.TargetPane
+	}
+	// --print-path では stdout をパス専用にするため、エラーは stderr に出す。
+	fail := func(err error) int {
+		if *printPath {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		} else {
+			fmt.Println("error:", err)
+		}
 		return 1
 	}
+
 	list, err := a.Cd.List()
+	if err != nil {
+		return fail(err)
+	}
+	if a.Select == nil && !*printPath {
+		return respondForCd(list)
+	}
+	if a.Select == nil {
+		return fail(errors.New("no selector configured"))
+	}
+	wt, err := a.Select(list, fs.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+	if *printPath {
+		fmt.Println(wt.Path)
+		return 0
+	}
+	if *noSession {
+		if err := a.Cd.Enter(wt); err != nil {
+			return fail(err)
+		}
+		fmt.Println(wt.Path)
+		return 0
+	}
+	if err := a.Cd.Launch(wt, opts); err != nil {
+		return fail(err)
+	}
+	return 0
+}
+
+func (a *App) runRename(args []string) int {
+	if len(args) != 2 {
+		fmt.Println("usage: gwm rename <branch> <new-branch>")
+		return 1
+	}
+	if a.Rename == nil {
+		fmt.Println("error: rename usecase not configured")
+		return 1
+	}
+	out, err := a.Rename.Execute(usecase.RenameInput{Branch: args[0], NewBranch: args[1]})
+	for _, m := range out.Messages {
+		fmt.Println(m)
+	}
 	if err != nil {
 		fmt.Println("error:", err)
 		return 1
 	}
-	if a.Select == nil {
-		return respondForCd(list)
+	return 0
+}
+
+func (a *App) runEnv(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("usage: gwm env <branch>")
+		return 1
+	}
+	if a.Env == nil {
+		fmt.Println("error: env usecase not configured")
+		return 1
 	}
-	wt, err := a.Select(list)
+	env, err := a.Env.Execute(args[0])
 	if err != nil {
 		fmt.Println("error:", err)
 		return 1
 	}
-	if err := a.Cd.Launch(wt); err != nil {
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		fmt.Printf("%s=%s\n", k, shellQuote(v))
+	}
+	return 0
+}
+
+// shellQuote quotes v for sh only when it contains special characters.
+func shellQuote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
+
+func (a *App) runUI(args []string) int {
+	if len(args) != 0 {
+		fmt.Println("usage: gwm ui")
+		return 1
+	}
+	if a.UI == nil {
+		fmt.Println("error: ui not configured")
+		return 1
+	}
+	if err := a.UI(); err != nil {
 		fmt.Println("error:", err)
 		return 1
 	}
 	return 0
 }
 
+func (a *App) runShellInit(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("usage: gwm shell-init bash|zsh|fish")
+		return 1
+	}
+	if a.ShellInit == nil {
+		fmt.Println("error: shell integration not configured")
+		return 1
+	}
+	script, err := a.ShellInit(args[0])
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	fmt.Print(script)
+	return 0
+}
+
 func (a *App) runRemove(args []string) int {
 	fs := flag.NewFlagSet("remove", flag.ContinueOnError)
 	fs.SetOutput(os.Stdout)
 	force := fs.Bool("force", false, "force removal even if dirty")
+	var deleteBranch branchDeletionFlag
+	fs.Var(&deleteBranch, "delete-branch", "also delete the branch (git branch -d); =force uses -D")
+	deleteRemote := fs.Bool("delete-remote", false, "also delete the upstream branch on its remote")
+	yes := fs.Bool("yes", false, "do not ask for confirmation when work would be lost")
+	archive := fs.Bool("archive", false, "snapshot changes and untracked files into .gwm/archive first")
 	if err := fs.Parse(reorderRemoveArgs(args)); err != nil {
 		return 1
 	}
@@ -169,10 +367,8 @@ func (a *App) runRemove(args []string) int {
 		return 1
 	}
 
-	branch := ""
-	if fs.NArg() == 1 {
-		branch = fs.Arg(0)
-	} else if fs.NArg() == 0 {
+	branches := fs.Args()
+	if len(branches) == 0 {
 		list, err := a.Remove.Worktrees.ListWorktrees()
 		if err != nil {
 			fmt.Println("error:", err)
@@ -182,32 +378,121 @@ func (a *App) runRemove(args []string) int {
 			fmt.Println("error: no worktrees")
 			return 1
 		}
-		if a.Select == nil {
+		if a.Select == nil && a.SelectMany == nil {
 			return respondForCd(list)
 		}
-		wt, err := a.Select(list)
-		if err != nil {
+		if branches, err = a.selectBranches(list); err != nil {
 			fmt.Println("error:", err)
 			return 1
 		}
-		branch = wt.Branch
-	} else {
-		fmt.Println("usage: gwm remove <branch> [--force]")
-		return 1
 	}
 
-	in := usecase.RemoveInput{Branch: branch, Force: *force}
+	in := usecase.RemoveInput{
+		Force:        *force,
+		DeleteBranch: usecase.BranchDeletion(deleteBranch),
+		DeleteRemote: *deleteRemote,
+		Archive:      *archive,
+	}
+	var failed []string
+	for _, branch := range branches {
+		in.Branch = branch
+		if !a.removeOne(in, *yes) {
+			failed = append(failed, branch)
+		}
+	}
+	return reportBulk("removed", len(branches), failed)
+}
+
+// removeOne inspects, confirms and removes a single worktree, printing the
+// interactor messages. It reports whether the worktree was removed.
+func (a *App) removeOne(in usecase.RemoveInput, yes bool) bool {
+	if !yes {
+		r, err := a.Remove.Inspect(in.Branch)
+		if err != nil {
+			fmt.Println("error:", err)
+			return false
+		}
+		if !r.Clean() {
+			fmt.Printf("removing %s will discard or interrupt:\n", in.Branch)
+			for _, f := range r.Findings() {
+				fmt.Println("  -", f)
+			}
+			if !a.confirm("remove anyway?") {
+				fmt.Println("aborted")
+				return false
+			}
+		}
+	}
+
 	out, err := a.Remove.Execute(in)
+	for _, m := range out.Messages {
+		fmt.Println(m)
+	}
 	if err != nil {
 		fmt.Println("error:", err)
-		return 1
+		return false
 	}
-	for _, m := range out.Messages {
-		fmt.Println(m)
+	return true
+}
+
+// selectBranches asks for one or more worktrees, preferring SelectMany, and
+// returns their branches.
+func (a *App) selectBranches(list []domain.WorktreeInfo) ([]string, error) {
+	if a.SelectMany == nil {
+		wt, err := a.Select(list, "")
+		if err != nil {
+			return nil, err
+		}
+		return []string{wt.Branch}, nil
+	}
+	picked, err := a.SelectMany(list, "")
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, wt := range picked {
+		if wt.Branch == "" {
+			fmt.Println("skipped (detached):", wt.Path)
+			continue
+		}
+		branches = append(branches, wt.Branch)
+	}
+	if len(branches) == 0 {
+		return nil, errors.New("no worktree with a branch selected")
+	}
+	return branches, nil
+}
+
+// reportBulk prints a summary when more than one item was processed and
+// returns the exit code: 1 if any item failed.
+func reportBulk(verb string, total int, failed []string) int {
+	if total > 1 {
+		fmt.Printf("%s %d of %d", verb, total-len(failed), total)
+		if len(failed) > 0 {
+			fmt.Printf("; failed: %s", strings.Join(failed, ", "))
+		}
+		fmt.Println()
+	}
+	if len(failed) > 0 {
+		return 1
 	}
 	return 0
 }
 
+func (a *App) confirm(prompt string) bool {
+	if a.Confirm != nil {
+		return a.Confirm(prompt)
+	}
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
 // respondForCd prints JSON to stdout so wrapper can use it; if empty, error.
 func respondForCd(list []domain.WorktreeInfo) int {
 	if len(list) == 0 {
@@ -225,6 +510,37 @@ func respondForCd(list []domain.WorktreeInfo) int {
 
 var ErrCancel = errors.New("cancelled")
 
+// stringList collects a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ",") }
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// branchDeletionFlag accepts both --delete-branch and --delete-branch=force.
+type branchDeletionFlag usecase.BranchDeletion
+
+func (b *branchDeletionFlag) String() string { return string(*b) }
+
+func (b *branchDeletionFlag) IsBoolFlag() bool { return true }
+
+func (b *branchDeletionFlag) Set(v string) error {
+	switch strings.ToLower(v) {
+	case "true":
+		*b = branchDeletionFlag(usecase.DeleteBranchSafe)
+	case "false":
+		*b = branchDeletionFlag(usecase.KeepBranch)
+	case "force":
+		*b = branchDeletionFlag(usecase.DeleteBranchForce)
+	default:
+		return fmt.Errorf("invalid value %q (want force)", v)
+	}
+	return nil
+}
+
 // reorderConfigAddArgs allows "gwm config add <path> --mode ..." by moving the
 // first positional argument to the end so that flag parsing still works.
 func reorderConfigAddArgs(args []string) []string {
@@ -237,7 +553,7 @@ func reorderConfigAddArgs(args []string) []string {
 	return args
 }
 
-func reorderRemoveArgs(args []string) []string {
+func reorderCreateArgs(args []string) []string {
 	if len(args) == 0 {
 		return args
 	}
@@ -246,3 +562,32 @@ func reorderRemoveArgs(args []string) []string {
 	}
 	return args
 }
+
+// reorderCdArgs allows "gwm cd <query> --print-path".
+func reorderCdArgs(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	if !strings.HasPrefix(args[0], "-") {
+		return append(args[1:], args[0])
+	}
+	return args
+}
+
+// reorderRemoveArgs moves every branch after the flags so that
+// "gwm remove a b --force" works; remove only has boolean flags.
+func reorderRemoveArgs(args []string) []string {
+	var flags, branches []string
+	for i, a := range args {
+		if a == "--" {
+			branches = append(branches, args[i+1:]...)
+			break
+		}
+		if strings.HasPrefix(a, "-") {
+			flags = append(flags, a)
+		} else {
+			branches = append(branches, a)
+		}
+	}
+	return append(append(flags, "--"), branches...)
+}
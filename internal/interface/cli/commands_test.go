@@ -2,6 +2,7 @@ package cli
 
 import (
 	"errors"
+	"flag"
 	"os"
 	"path/filepath"
 	"testing"
@@ -19,8 +20,11 @@ type stubWorktrees struct {
 	force  bool
 }
 
-func (s *stubWorktrees) BranchExists(string) (bool, error)  { return false, nil }
-func (s *stubWorktrees) CreateBranch(string) error          { return nil }
+func (s *stubWorktrees) BranchExists(string) (bool, error) { return false, nil }
+func (s *stubWorktrees) CreateBranch(string, string, bool) (string, error) {
+	return "main", nil
+}
+func (s *stubWorktrees) DeleteBranch(string, bool) error    { return nil }
 func (s *stubWorktrees) AddWorktree(string) (string, error) { return "", nil }
 func (s *stubWorktrees) ListWorktrees() ([]domain.WorktreeInfo, error) {
 	if s.branch == "" {
@@ -28,16 +32,46 @@ func (s *stubWorktrees) ListWorktrees() ([]domain.WorktreeInfo, error) {
 	}
 	return []domain.WorktreeInfo{{Branch: s.branch, Path: "/tmp/worktrees/" + s.branch}}, nil
 }
+func (s *stubWorktrees) BranchStatuses(string) (map[string]domain.BranchStatus, error) {
+	return nil, nil
+}
+func (s *stubWorktrees) PruneWorktrees() error { return nil }
+func (s *stubWorktrees) Upstream(string) (string, string, bool, error) {
+	return "", "", false, nil
+}
+func (s *stubWorktrees) UnpushedCommits(string, ...string) (int, error) { return 0, nil }
+func (s *stubWorktrees) DeleteRemoteBranch(string, string) error        { return nil }
+func (s *stubWorktrees) Inspect(wt domain.WorktreeInfo) (domain.RemovalInspection, error) {
+	return domain.RemovalInspection{Branch: wt.Branch, Path: wt.Path}, nil
+}
+func (s *stubWorktrees) CaptureChanges(string) (domain.ChangeSet, error) {
+	return domain.ChangeSet{}, nil
+}
+func (s *stubWorktrees) ApplyPatch(string, []byte, bool) error    { return nil }
+func (s *stubWorktrees) Head(string) (string, error)              { return "", nil }
+func (s *stubWorktrees) LastCommit(string) (domain.Commit, error) { return domain.Commit{}, nil }
+func (s *stubWorktrees) ShortStatus(string) ([]string, error)     { return nil, nil }
+func (s *stubWorktrees) Describe(wt domain.WorktreeInfo) (domain.WorktreeInfo, error) {
+	return wt, nil
+}
 func (s *stubWorktrees) RemoveWorktree(branch string, force bool) (string, error) {
 	s.branch = branch
 	s.force = force
 	return "/tmp/worktrees/" + branch, nil
 }
+func (s *stubWorktrees) RenameWorktree(wt domain.WorktreeInfo, newBranch string) (string, error) {
+	s.branch = newBranch
+	return "/tmp/worktrees/" + newBranch, nil
+}
 
 type stubLauncher struct{}
 
-func (stubLauncher) Launch(domain.WorktreeInfo) error { return nil }
-func (stubLauncher) Kill(domain.WorktreeInfo) error   { return nil }
+func (stubLauncher) Launch(domain.WorktreeInfo, domain.LaunchOptions) error { return nil }
+func (stubLauncher) Kill(domain.WorktreeInfo) error                         { return nil }
+func (stubLauncher) Windows(domain.WorktreeInfo) (int, error)               { return 0, nil }
+func (stubLauncher) HasSession(domain.WorktreeInfo) (bool, error) {
+	return false, nil
+}
 
 func (m *memoryConfigRepo) Load() ([]domain.ConfigEntry, error) {
 	return append([]domain.ConfigEntry{}, m.entries...), nil
@@ -72,7 +106,7 @@ func TestRunConfigAddAllowsModeAfterPath(t *testing.T) {
 }
 
 func TestRunRemoveAcceptsBranchBeforeFlag(t *testing.T) {
-	wt := &stubWorktrees{}
+	wt := &stubWorktrees{branch: "feature/foo"}
 	app := &App{Remove: &usecase.RemoveInteractor{Worktrees: wt, Launcher: stubLauncher{}}}
 
 	if exit := app.runRemove([]string{"feature/foo", "--force"}); exit != 0 {
@@ -83,9 +117,86 @@ func TestRunRemoveAcceptsBranchBeforeFlag(t *testing.T) {
 	}
 }
 
+type linkedWorktrees struct{ stubWorktrees }
+
+func (s *linkedWorktrees) ListWorktrees() ([]domain.WorktreeInfo, error) {
+	return []domain.WorktreeInfo{{Branch: "refs/heads/main", Path: "/repo"}, {Branch: "refs/heads/feat", Path: "/tmp/worktrees/feat"}}, nil
+}
+
+func TestRunRename(t *testing.T) {
+	wt := &linkedWorktrees{}
+	app := &App{Rename: &usecase.RenameInteractor{Worktrees: wt}}
+
+	if exit := app.runRename([]string{"feat"}); exit != 1 {
+		t.Fatalf("missing new name should fail, got %d", exit)
+	}
+	if exit := app.runRename([]string{"main", "trunk"}); exit != 1 || wt.branch != "" {
+		t.Fatalf("main worktree should not be renamed: exit=%d renamed=%q", exit, wt.branch)
+	}
+	if exit := app.runRename([]string{"feat", "feat2"}); exit != 0 || wt.branch != "feat2" {
+		t.Fatalf("runRename returned %d, renamed=%q", exit, wt.branch)
+	}
+}
+
+type dirtyWorktrees struct {
+	stubWorktrees
+	removed bool
+}
+
+func (s *dirtyWorktrees) Inspect(wt domain.WorktreeInfo) (domain.RemovalInspection, error) {
+	return domain.RemovalInspection{Branch: wt.Branch, Untracked: []string{"dump.sql"}}, nil
+}
+
+func (s *dirtyWorktrees) RemoveWorktree(branch string, force bool) (string, error) {
+	s.removed = true
+	return s.stubWorktrees.RemoveWorktree(branch, force)
+}
+
+func TestRunRemoveAsksBeforeDiscardingWork(t *testing.T) {
+	wt := &dirtyWorktrees{stubWorktrees: stubWorktrees{branch: "feature"}}
+	var prompts []string
+	app := &App{
+		Remove:  &usecase.RemoveInteractor{Worktrees: wt, Launcher: stubLauncher{}},
+		Confirm: func(p string) bool { prompts = append(prompts, p); return false },
+	}
+
+	if exit := app.runRemove([]string{"feature", "--force"}); exit == 0 {
+		t.Fatal("declined removal should fail")
+	}
+	if wt.removed || len(prompts) != 1 {
+		t.Fatalf("removed=%v prompts=%q", wt.removed, prompts)
+	}
+
+	if exit := app.runRemove([]string{"feature", "--force", "--yes"}); exit != 0 {
+		t.Fatalf("runRemove --yes returned %d", exit)
+	}
+	if !wt.removed || len(prompts) != 1 {
+		t.Fatalf("--yes should skip the prompt: removed=%v prompts=%q", wt.removed, prompts)
+	}
+}
+
+func TestBranchDeletionFlag(t *testing.T) {
+	cases := map[string]usecase.BranchDeletion{
+		"--delete-branch":       usecase.DeleteBranchSafe,
+		"--delete-branch=force": usecase.DeleteBranchForce,
+		"--delete-branch=false": usecase.KeepBranch,
+	}
+	for arg, want := range cases {
+		var v branchDeletionFlag
+		fs := flag.NewFlagSet("remove", flag.ContinueOnError)
+		fs.Var(&v, "delete-branch", "")
+		if err := fs.Parse([]string{arg}); err != nil {
+			t.Fatalf("%s: %v", arg, err)
+		}
+		if usecase.BranchDeletion(v) != want {
+			t.Fatalf("%s: got %q, want %q", arg, v, want)
+		}
+	}
+}
+
 func TestRunRemoveWithoutBranchUsesSelector(t *testing.T) {
 	wt := &stubWorktrees{branch: "feature/foo"}
-	selector := func(list []domain.WorktreeInfo) (domain.WorktreeInfo, error) {
+	selector := func(list []domain.WorktreeInfo, _ string) (domain.WorktreeInfo, error) {
 		if len(list) == 0 {
 			return domain.WorktreeInfo{}, errors.New("empty")
 		}
@@ -100,3 +211,236 @@ func TestRunRemoveWithoutBranchUsesSelector(t *testing.T) {
 		t.Fatalf("runRemove returned %d", exit)
 	}
 }
+
+type stubCdWorktrees struct {
+	stubWorktrees
+	list []domain.WorktreeInfo
+}
+
+func (s *stubCdWorktrees) ListWorktrees() ([]domain.WorktreeInfo, error) {
+	return s.list, nil
+}
+
+type recordingLauncher struct {
+	launched []domain.WorktreeInfo
+	opts     domain.LaunchOptions
+}
+
+func (l *recordingLauncher) Launch(wt domain.WorktreeInfo, opts domain.LaunchOptions) error {
+	l.launched = append(l.launched, wt)
+	l.opts = opts
+	return nil
+}
+func (l *recordingLauncher) Kill(domain.WorktreeInfo) error           { return nil }
+func (l *recordingLauncher) Windows(domain.WorktreeInfo) (int, error) { return 0, nil }
+func (l *recordingLauncher) HasSession(domain.WorktreeInfo) (bool, error) {
+	return false, nil
+}
+
+func TestRunCdPrintPathSkipsLauncher(t *testing.T) {
+	wt := &stubCdWorktrees{list: []domain.WorktreeInfo{{Branch: "refs/heads/foo", Path: "/tmp/worktrees/foo"}}}
+	launcher := &recordingLauncher{}
+	app := &App{
+		Cd:     &usecase.CdInteractor{Worktrees: wt, Launcher: launcher},
+		Select: func(list []domain.WorktreeInfo, _ string) (domain.WorktreeInfo, error) { return list[0], nil },
+	}
+
+	if exit := app.runCd([]string{"--print-path"}); exit != 0 {
+		t.Fatalf("runCd returned %d", exit)
+	}
+	if len(launcher.launched) != 0 {
+		t.Fatalf("launcher should not be called with --print-path")
+	}
+}
+
+func TestRunCdTargets(t *testing.T) {
+	wt := &stubCdWorktrees{list: []domain.WorktreeInfo{{Branch: "refs/heads/foo", Path: "/tmp/worktrees/foo"}}}
+	launcher := &recordingLauncher{}
+	app := &App{
+		Cd:     &usecase.CdInteractor{Worktrees: wt, Launcher: launcher},
+		Select: func(list []domain.WorktreeInfo, _ string) (domain.WorktreeInfo, error) { return list[0], nil },
+	}
+
+	if exit := app.runCd([]string{"foo", "--split"}); exit != 0 {
+		t.Fatalf("runCd returned %d", exit)
+	}
+	if launcher.opts.Target != domain.TargetPane {
+		t.Fatalf("target = %q, want pane", launcher.opts.Target)
+	}
+	if exit := app.runCd([]string{"--new-window"}); exit != 0 || launcher.opts.Target != domain.TargetWindow {
+		t.Fatalf("runCd --new-window = %d, target %q", exit, launcher.opts.Target)
+	}
+	if exit := app.runCd([]string{"--new-window", "--split"}); exit == 0 {
+		t.Fatal("--new-window and --split should be exclusive")
+	}
+	if exit := app.runCd([]string{"foo", "--detach"}); exit != 0 || !launcher.opts.Detach || launcher.opts.Target != domain.TargetSession {
+		t.Fatalf("runCd --detach = %d, opts %+v", exit, launcher.opts)
+	}
+	launched := len(launcher.launched)
+	if exit := app.runCd([]string{"foo", "--no-session"}); exit != 0 || len(launcher.launched) != launched {
+		t.Fatalf("runCd --no-session = %d, launches %d -> %d", exit, launched, len(launcher.launched))
+	}
+	if exit := app.runCd([]string{"--no-session", "--detach"}); exit == 0 {
+		t.Fatal("--no-session and --detach should be exclusive")
+	}
+	for _, flag := range []string{"--new-window", "--split", "--detach", "--no-session"} {
+		if exit := app.runCd([]string{"--print-path", flag}); exit == 0 {
+			t.Fatalf("--print-path and %s should be exclusive", flag)
+		}
+	}
+}
+
+func TestRunShellInitRequiresShell(t *testing.T) {
+	var got string
+	app := &App{ShellInit: func(shell string) (string, error) {
+		got = shell
+		return "", nil
+	}}
+
+	if exit := app.runShellInit(nil); exit == 0 {
+		t.Fatalf("expected failure without shell name")
+	}
+	if exit := app.runShellInit([]string{"zsh"}); exit != 0 {
+		t.Fatalf("runShellInit returned %d", exit)
+	}
+	if got != "zsh" {
+		t.Fatalf("ShellInit called with %q", got)
+	}
+}
+
+func TestRunWithoutArgsStartsUI(t *testing.T) {
+	calls := 0
+	app := &App{UI: func() error { calls++; return nil }}
+
+	if exit := app.Run(nil); exit != 0 || calls != 1 {
+		t.Fatalf("Run(nil) = %d, UI calls = %d", exit, calls)
+	}
+	if exit := app.Run([]string{"ui"}); exit != 0 || calls != 2 {
+		t.Fatalf("Run(ui) = %d, UI calls = %d", exit, calls)
+	}
+	if exit := (&App{}).Run(nil); exit != 1 {
+		t.Fatalf("Run(nil) without UI should print usage, got %d", exit)
+	}
+}
+
+type bulkWorktrees struct {
+	stubCdWorktrees
+	removed []string
+}
+
+func (s *bulkWorktrees) RemoveWorktree(branch string, force bool) (string, error) {
+	if branch == "missing" {
+		return "", errors.New("not a worktree")
+	}
+	s.removed = append(s.removed, branch)
+	return "/tmp/worktrees/" + branch, nil
+}
+
+func TestRunRemoveMultipleBranchesContinuesOnFailure(t *testing.T) {
+	wt := &bulkWorktrees{stubCdWorktrees: stubCdWorktrees{list: []domain.WorktreeInfo{
+		{Branch: "refs/heads/a", Path: "/tmp/worktrees/a"},
+		{Branch: "refs/heads/b", Path: "/tmp/worktrees/b"},
+	}}}
+	app := &App{Remove: &usecase.RemoveInteractor{Worktrees: wt, Launcher: stubLauncher{}}}
+
+	if exit := app.runRemove([]string{"a", "missing", "--yes", "b"}); exit != 1 {
+		t.Fatalf("a failed item should make runRemove fail, got %d", exit)
+	}
+	if len(wt.removed) != 2 || wt.removed[0] != "a" || wt.removed[1] != "b" {
+		t.Fatalf("removed = %q", wt.removed)
+	}
+}
+
+func TestRunRemoveUsesMultiSelect(t *testing.T) {
+	wt := &bulkWorktrees{stubCdWorktrees: stubCdWorktrees{list: []domain.WorktreeInfo{
+		{Branch: "refs/heads/a", Path: "/tmp/worktrees/a"},
+		{Path: "/tmp/detached"},
+		{Branch: "refs/heads/b", Path: "/tmp/worktrees/b"},
+	}}}
+	app := &App{
+		Remove:     &usecase.RemoveInteractor{Worktrees: wt, Launcher: stubLauncher{}},
+		SelectMany: func(list []domain.WorktreeInfo, _ string) ([]domain.WorktreeInfo, error) { return list, nil },
+	}
+
+	if exit := app.runRemove([]string{"--yes"}); exit != 0 {
+		t.Fatalf("runRemove returned %d", exit)
+	}
+	if len(wt.removed) != 2 {
+		t.Fatalf("removed = %q", wt.removed)
+	}
+}
+
+type sessionLauncher struct {
+	stubLauncher
+	running map[string]bool
+}
+
+func (l *sessionLauncher) HasSession(wt domain.WorktreeInfo) (bool, error) {
+	return l.running[wt.Path], nil
+}
+
+func (l *sessionLauncher) Kill(wt domain.WorktreeInfo) error {
+	delete(l.running, wt.Path)
+	return nil
+}
+
+func TestRunKillSession(t *testing.T) {
+	wt := &stubCdWorktrees{list: []domain.WorktreeInfo{
+		{Branch: "refs/heads/a", Path: "/tmp/worktrees/a"},
+		{Branch: "refs/heads/b", Path: "/tmp/worktrees/b"},
+	}}
+	launcher := &sessionLauncher{running: map[string]bool{"/tmp/worktrees/a": true}}
+	app := &App{Session: &usecase.SessionInteractor{Worktrees: wt, Launcher: launcher}}
+
+	if exit := app.Run([]string{"kill-session", "a"}); exit != 0 {
+		t.Fatalf("kill-session a returned %d", exit)
+	}
+	if launcher.running["/tmp/worktrees/a"] {
+		t.Fatal("session a should be killed")
+	}
+	if exit := app.Run([]string{"kill-session", "a", "b"}); exit != 1 {
+		t.Fatalf("killing sessions that are not running should fail, got %d", exit)
+	}
+}
+
+type stubSessionLister struct {
+	sessions []domain.SessionInfo
+	killed   []string
+}
+
+func (l *stubSessionLister) Sessions() ([]domain.SessionInfo, error) { return l.sessions, nil }
+func (l *stubSessionLister) AttachSession(string) error              { return nil }
+func (l *stubSessionLister) KillSession(name string) error {
+	l.killed = append(l.killed, name)
+	return nil
+}
+
+func TestRunSessionsGC(t *testing.T) {
+	repoDir := t.TempDir()
+	wt := &stubCdWorktrees{list: []domain.WorktreeInfo{{Branch: "refs/heads/a", Path: repoDir + "/worktrees/a"}}}
+	lister := &stubSessionLister{sessions: []domain.SessionInfo{
+		{Name: "gwm-a", Path: repoDir + "/worktrees/a"},
+		{Name: "gwm-b", Path: repoDir + "/worktrees/b"},
+	}}
+	var prompts []string
+	app := &App{
+		Session: &usecase.SessionInteractor{Worktrees: wt, Lister: lister, RepoDir: repoDir},
+		Confirm: func(p string) bool { prompts = append(prompts, p); return false },
+	}
+
+	if exit := app.Run([]string{"sessions"}); exit != 0 {
+		t.Fatalf("sessions returned %d", exit)
+	}
+	if exit := app.Run([]string{"sessions", "gc", "--dry-run"}); exit != 0 || len(lister.killed) != 0 {
+		t.Fatalf("dry run returned %d and killed %v", exit, lister.killed)
+	}
+	if exit := app.Run([]string{"sessions", "gc"}); exit != 1 || len(prompts) != 1 || len(lister.killed) != 0 {
+		t.Fatalf("declined gc returned %d, prompts %v, killed %v", exit, prompts, lister.killed)
+	}
+	if exit := app.Run([]string{"sessions", "gc", "--yes"}); exit != 0 {
+		t.Fatalf("gc --yes returned %d", exit)
+	}
+	if len(lister.killed) != 1 || lister.killed[0] != "gwm-b" {
+		t.Fatalf("only the orphan should be killed, got %v", lister.killed)
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func (a *App) runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	format := fs.String("format", "table", "table|json|tsv or a Go template such as '{{.Branch}}'")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 0 {
+		fmt.Println("usage: gwm list [--format table|json|tsv|<template>]")
+		return 1
+	}
+	if a.List == nil {
+		fmt.Println("error: list usecase not configured")
+		return 1
+	}
+	list, err := a.List.Execute()
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	if err := writeList(os.Stdout, list, *format, time.Now()); err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	return 0
+}
+
+func writeList(w io.Writer, list []domain.WorktreeInfo, format string, now time.Time) error {
+	switch format {
+	case "json":
+		if list == nil {
+			list = []domain.WorktreeInfo{}
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "table", "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tBRANCH\tPATH\tHEAD\tSTATE\tUPSTREAM\tSESSION\tLAST USED")
+		for _, wt := range list {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				currentMark(wt), branchLabel(wt), wt.Path, domain.ShortHash(wt.Head), stateLabel(wt),
+				upstreamLabel(wt), sessionLabel(wt), lastUsedLabel(wt.LastUsed, now))
+		}
+		return tw.Flush()
+	case "tsv":
+		for _, wt := range list {
+			lastUsed := ""
+			if !wt.LastUsed.IsZero() {
+				lastUsed = wt.LastUsed.Format(time.RFC3339)
+			}
+			fields := []string{wt.Branch, wt.Path, wt.Head, fmt.Sprint(wt.IsCurrent), fmt.Sprint(wt.Dirty),
+				wt.Upstream, fmt.Sprint(wt.Ahead), fmt.Sprint(wt.Behind), fmt.Sprint(wt.HasSession), lastUsed,
+				strings.Join(wt.Flags(), ",")}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if !strings.Contains(format, "{{") {
+			return fmt.Errorf("unknown format: %s", format)
+		}
+		tmpl, err := template.New("format").Parse(format)
+		if err != nil {
+			return err
+		}
+		for _, wt := range list {
+			if err := tmpl.Execute(w, wt); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func currentMark(wt domain.WorktreeInfo) string {
+	if wt.IsCurrent {
+		return "*"
+	}
+	return ""
+}
+
+func branchLabel(wt domain.WorktreeInfo) string {
+	switch {
+	case wt.Branch != "":
+		return wt.Branch
+	case wt.Bare:
+		return "(bare)"
+	default:
+		return "(detached)"
+	}
+}
+
+// stateLabel は dirty/clean に locked などの状態を付け加える。
+func stateLabel(wt domain.WorktreeInfo) string {
+	state := "clean"
+	if wt.Dirty {
+		state = "dirty"
+	}
+	if wt.Bare || wt.Prunable {
+		state = "-"
+	}
+	if flags := wt.Flags(); len(flags) > 0 {
+		state += "," + strings.Join(flags, ",")
+	}
+	return strings.TrimPrefix(state, "-,")
+}
+
+func upstreamLabel(wt domain.WorktreeInfo) string {
+	if wt.Upstream == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s +%d -%d", wt.Upstream, wt.Ahead, wt.Behind)
+}
+
+func sessionLabel(wt domain.WorktreeInfo) string {
+	if wt.HasSession {
+		return "running"
+	}
+	return "-"
+}
+
+func lastUsedLabel(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/example/gwm/internal/domain"
+)
+
+func TestWriteListFormats(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	list := []domain.WorktreeInfo{
+		{Branch: "main", Path: "/repo", IsCurrent: true, Head: "0123456789abcdef"},
+		{Path: "/repo/worktrees/old", Detached: true, Locked: true, LockReason: "usb disk"},
+		{Branch: "feature/foo", Path: "/repo/worktrees/feature/foo", Dirty: true, Upstream: "origin/feature/foo", Ahead: 2, Behind: 1, HasSession: true, LastUsed: now.Add(-3 * time.Hour)},
+	}
+
+	var buf bytes.Buffer
+	if err := writeList(&buf, list, "table", now); err != nil {
+		t.Fatalf("table: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"BRANCH", "0123456", "dirty", "origin/feature/foo +2 -1", "running", "3h ago", "(detached)", "clean,detached,locked"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("table output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := writeList(&buf, list, "{{.Branch}}:{{.Ahead}}", now); err != nil {
+		t.Fatalf("template: %v", err)
+	}
+	if buf.String() != "main:0\n:0\nfeature/foo:2\n" {
+		t.Fatalf("template output = %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeList(&buf, list, "json", now); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded []domain.WorktreeInfo
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 3 || decoded[1].LockReason != "usb disk" {
+		t.Fatalf("json output invalid: %v", err)
+	}
+
+	buf.Reset()
+	if err := writeList(&buf, list, "tsv", now); err != nil {
+		t.Fatalf("tsv: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 3 || strings.Count(lines[0], "\t") != 10 {
+		t.Fatalf("tsv output = %q", buf.String())
+	}
+
+	if err := writeList(&buf, list, "yaml", now); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
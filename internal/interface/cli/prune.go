@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/example/gwm/internal/app/usecase"
+)
+
+func (a *App) runPrune(args []string) int {
+	fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	dryRun := fs.Bool("dry-run", false, "only list the worktrees that would be removed")
+	mergedInto := fs.String("merged-into", "", "ref branches must be merged into (default: default branch)")
+	staleDays := fs.Int("stale-days", 0, "also remove worktrees untouched for N days")
+	deleteBranch := fs.Bool("delete-branch", false, "delete the local branch after removing the worktree")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 0 {
+		fmt.Println("usage: gwm prune [--dry-run] [--merged-into <ref>] [--stale-days N] [--delete-branch] [--yes]")
+		return 1
+	}
+	if a.Prune == nil {
+		fmt.Println("error: prune usecase not configured")
+		return 1
+	}
+
+	in := usecase.PruneInput{MergedInto: *mergedInto, StaleDays: *staleDays, DeleteBranch: *deleteBranch}
+	candidates, err := a.Prune.Plan(in)
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	if len(candidates) == 0 {
+		fmt.Println("nothing to prune")
+		return 0
+	}
+	for _, c := range candidates {
+		fmt.Printf("%s\t%s\t(%s)\n", c.Worktree.ShortBranch(), c.Worktree.Path, strings.Join(c.Reasons, ", "))
+	}
+	if *dryRun {
+		return 0
+	}
+	if !*yes && !a.confirm(fmt.Sprintf("remove %d worktree(s)?", len(candidates))) {
+		fmt.Println("cancelled")
+		return 1
+	}
+
+	out := a.Prune.Execute(candidates, in)
+	for _, m := range out.Messages {
+		fmt.Println(m)
+	}
+	if out.Failed > 0 {
+		fmt.Printf("error: %d operation(s) failed\n", out.Failed)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/example/gwm/internal/app/usecase"
+)
+
+func (a *App) runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 1 {
+		fmt.Println("usage: gwm restore <archive>")
+		return 1
+	}
+	if a.Restore == nil {
+		fmt.Println("error: restore usecase not configured")
+		return 1
+	}
+
+	out, err := a.Restore.Execute(usecase.RestoreInput{Archive: fs.Arg(0)})
+	for _, m := range out.Messages {
+		fmt.Println(m)
+	}
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	fmt.Println("worktree:", out.Worktree)
+	return 0
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/example/gwm/internal/app/usecase"
+)
+
+func (a *App) runKillSession(args []string) int {
+	fs := flag.NewFlagSet("kill-session", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if a.Session == nil {
+		fmt.Println("error: session usecase not configured")
+		return 1
+	}
+
+	branches := fs.Args()
+	if len(branches) == 0 {
+		list, err := a.Session.Worktrees.ListWorktrees()
+		if err != nil {
+			fmt.Println("error:", err)
+			return 1
+		}
+		if len(list) == 0 {
+			fmt.Println("error: no worktrees")
+			return 1
+		}
+		if a.Select == nil && a.SelectMany == nil {
+			fmt.Println("usage: gwm kill-session <branch>...")
+			return 1
+		}
+		if branches, err = a.selectBranches(list); err != nil {
+			fmt.Println("error:", err)
+			return 1
+		}
+	}
+
+	var failed []string
+	for _, branch := range branches {
+		err := a.Session.KillBranch(branch)
+		switch {
+		case errors.Is(err, usecase.ErrNoSession):
+			fmt.Println("no session:", branch)
+			failed = append(failed, branch)
+		case err != nil:
+			fmt.Printf("error: %s: %v\n", branch, err)
+			failed = append(failed, branch)
+		default:
+			fmt.Println("session killed:", branch)
+		}
+	}
+	return reportBulk("killed", len(branches), failed)
+}
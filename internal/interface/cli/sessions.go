@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/example/gwm/internal/app/usecase"
+)
+
+const sessionsUsage = "usage: gwm sessions [list | attach <session|branch> | gc [--dry-run] [--yes]]"
+
+func (a *App) runSessions(args []string) int {
+	if a.Session == nil {
+		fmt.Println("error: session usecase not configured")
+		return 1
+	}
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	switch sub {
+	case "list":
+		if len(args) != 0 {
+			fmt.Println(sessionsUsage)
+			return 1
+		}
+		entries, err := a.Session.Sessions()
+		if err != nil {
+			fmt.Println("error:", err)
+			return 1
+		}
+		if len(entries) == 0 {
+			fmt.Println("no sessions")
+			return 0
+		}
+		if err := writeSessions(os.Stdout, entries, time.Now()); err != nil {
+			fmt.Println("error:", err)
+			return 1
+		}
+		return 0
+	case "attach":
+		if len(args) != 1 {
+			fmt.Println("usage: gwm sessions attach <session|branch>")
+			return 1
+		}
+		if err := a.Session.Attach(args[0]); err != nil {
+			fmt.Println("error:", err)
+			return 1
+		}
+		return 0
+	case "gc":
+		return a.runSessionsGC(args)
+	default:
+		fmt.Println(sessionsUsage)
+		return 1
+	}
+}
+
+// runSessionsGC kills sessions whose worktree no longer exists.
+func (a *App) runSessionsGC(args []string) int {
+	fs := flag.NewFlagSet("sessions gc", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	dryRun := fs.Bool("dry-run", false, "only list the orphaned sessions")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 0 {
+		fmt.Println("usage: gwm sessions gc [--dry-run] [--yes]")
+		return 1
+	}
+	entries, err := a.Session.Sessions()
+	if err != nil {
+		fmt.Println("error:", err)
+		return 1
+	}
+	var orphans []usecase.SessionEntry
+	for _, e := range entries {
+		if e.Orphan {
+			orphans = append(orphans, e)
+		}
+	}
+	if len(orphans) == 0 {
+		fmt.Println("no orphaned sessions")
+		return 0
+	}
+	for _, e := range orphans {
+		fmt.Printf("  %s\t%s\n", e.Name, e.Path)
+	}
+	if *dryRun {
+		return 0
+	}
+	if !*yes && !a.confirm(fmt.Sprintf("kill %d session(s)?", len(orphans))) {
+		fmt.Println("cancelled")
+		return 1
+	}
+
+	var failed []string
+	for _, e := range orphans {
+		if err := a.Session.KillOrphan(e); err != nil {
+			fmt.Printf("error: %s: %v\n", e.Name, err)
+			failed = append(failed, e.Name)
+			continue
+		}
+		fmt.Println("session killed:", e.Name)
+	}
+	return reportBulk("killed", len(orphans), failed)
+}
+
+func writeSessions(w io.Writer, entries []usecase.SessionEntry, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SESSION\tWORKTREE\tATTACHED\tWINDOWS\tCREATED")
+	for _, e := range entries {
+		attached := "no"
+		if e.Attached {
+			attached = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", e.Name, sessionWorktreeLabel(e), attached, e.Windows, lastUsedLabel(e.Created, now))
+	}
+	return tw.Flush()
+}
+
+func sessionWorktreeLabel(e usecase.SessionEntry) string {
+	switch {
+	case e.Worktree != nil:
+		return branchLabel(*e.Worktree)
+	case e.Orphan:
+		return "(orphan)"
+	default:
+		return "(other repository)"
+	}
+}
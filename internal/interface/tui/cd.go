@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 
@@ -12,31 +14,50 @@ import (
 	"github.com/example/gwm/internal/domain"
 )
 
-// SelectWorktree shows a Bubble Tea list UI and returns the chosen worktree.
-func SelectWorktree(wts []domain.WorktreeInfo) (domain.WorktreeInfo, error) {
+const (
+	pickerHelp = "type to filter, Enter to attach, Esc to cancel, digits to jump"
+	multiHelp  = "space to toggle, a for all, / to filter, Enter to confirm, Esc to cancel"
+)
+
+var (
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	normalStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
+	matchStyle    = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("214"))
+	numberStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// Picker shows the worktree selection UI.
+type Picker struct {
+	// Preview loads the details shown beside the list for the highlighted
+	// worktree. It runs in the background; nil hides the preview pane.
+	Preview func(domain.WorktreeInfo) (domain.WorktreePreview, error)
+}
+
+// SelectWorktree shows a Bubble Tea list UI without a preview pane and
+// returns the chosen worktree.
+func SelectWorktree(wts []domain.WorktreeInfo, query string) (domain.WorktreeInfo, error) {
+	return Picker{}.Select(wts, query)
+}
+
+// Select returns the chosen worktree. query pre-fills the fuzzy filter; when
+// exactly one worktree matches it, that worktree is returned without showing the UI.
+func (p Picker) Select(wts []domain.WorktreeInfo, query string) (domain.WorktreeInfo, error) {
 	if len(wts) == 0 {
 		return domain.WorktreeInfo{}, fmt.Errorf("no worktrees found")
 	}
-	items := make([]list.Item, len(wts))
-	for i, wt := range wts {
-		items[i] = worktreeItem{info: wt}
+	if query != "" {
+		if matches := Filter(wts, query); len(matches) == 1 {
+			return matches[0], nil
+		}
 	}
 
-	styles := list.NewDefaultDelegate()
-	styles.ShowDescription = false
-	styles.Styles.SelectedTitle = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
-	styles.Styles.NormalTitle = lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
-
-	l := list.New(items, styles, 0, 0)
-	l.Title = "Select worktree (Enter to attach, q/Esc to cancel, digits to jump)"
-	l.SetShowHelp(false)
-	l.SetShowFilter(false)
-	l.SetShowStatusBar(false)
-	l.DisableQuitKeybindings() // handle quit keys ourselves
-
-	m := model{list: l}
-	p := tea.NewProgram(m)
-	res, err := p.Run()
+	m := newModel(wts, query)
+	if p.Preview != nil {
+		m.preview = p.Preview
+		m.previews = map[string]previewResult{}
+	}
+	// UI は stderr に描画し、stdout は `gwm cd --print-path` のパス出力用に空けておく。
+	res, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
 	if err != nil {
 		return domain.WorktreeInfo{}, err
 	}
@@ -47,45 +68,291 @@ func SelectWorktree(wts []domain.WorktreeInfo) (domain.WorktreeInfo, error) {
 	return *final.selected, nil
 }
 
+// SelectWorktrees lets the user pick several worktrees without a preview pane.
+func SelectWorktrees(wts []domain.WorktreeInfo, query string) ([]domain.WorktreeInfo, error) {
+	return Picker{}.SelectMany(wts, query)
+}
+
+// SelectMany is the multi-select variant of Select: space toggles the
+// highlighted worktree, a toggles every filtered one and / starts filtering.
+// Enter without any toggled worktree returns the highlighted one.
+func (p Picker) SelectMany(wts []domain.WorktreeInfo, query string) ([]domain.WorktreeInfo, error) {
+	if len(wts) == 0 {
+		return nil, fmt.Errorf("no worktrees found")
+	}
+	if query != "" {
+		if matches := Filter(wts, query); len(matches) == 1 {
+			return matches, nil
+		}
+	}
+
+	m := newMultiModel(wts, query)
+	if p.Preview != nil {
+		m.preview = p.Preview
+		m.previews = map[string]previewResult{}
+	}
+	res, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return nil, err
+	}
+	final := res.(model)
+	if final.cancelled || len(final.picked) == 0 {
+		return nil, fmt.Errorf("selection cancelled")
+	}
+	return final.picked, nil
+}
+
+// Filter returns the worktrees whose path or branch fuzzy-matches query,
+// best match first, using the same matching as the picker.
+func Filter(wts []domain.WorktreeInfo, query string) []domain.WorktreeInfo {
+	targets := make([]string, len(wts))
+	for i, wt := range wts {
+		targets[i] = worktreeItem{info: wt}.FilterValue()
+	}
+	var matches []domain.WorktreeInfo
+	for _, r := range list.DefaultFilter(query, targets) {
+		matches = append(matches, wts[r.Index])
+	}
+	return matches
+}
+
 type model struct {
-	list      list.Model
-	selected  *domain.WorktreeInfo
-	cancelled bool
+	list list.Model
+	// query is the fuzzy filter typed so far.
+	query string
+	// jump accumulates digits typed while query is empty.
+	jump string
+	// title and help make up the list title while no query is typed.
+	title, help string
+	selected    *domain.WorktreeInfo
+	cancelled   bool
+
+	// chosen holds the toggled worktree paths; non-nil turns on multi-select,
+	// where typing filters only after "/" (filtering).
+	chosen    map[string]bool
+	filtering bool
+	picked    []domain.WorktreeInfo
+
+	preview func(domain.WorktreeInfo) (domain.WorktreePreview, error)
+	// previews caches loaded previews by worktree path while the picker is open.
+	previews      map[string]previewResult
+	width, height int
+}
+
+func newModel(wts []domain.WorktreeInfo, query string) model {
+	items := make([]list.Item, len(wts))
+	for i, wt := range wts {
+		items[i] = worktreeItem{info: wt}
+	}
+
+	l := list.New(items, itemDelegate{}, 0, 0)
+	l.SetShowHelp(false)
+	l.SetShowFilter(false) // クエリはタイトル行に出す
+	l.SetShowStatusBar(false)
+	l.DisableQuitKeybindings() // handle quit keys ourselves
+
+	m := model{list: l, title: "Select worktree", help: pickerHelp}
+	m.setQuery(query)
+	return m
 }
 
-func (m model) Init() tea.Cmd { return nil }
+func newMultiModel(wts []domain.WorktreeInfo, query string) model {
+	m := newModel(wts, query)
+	m.chosen = map[string]bool{}
+	m.list.SetDelegate(itemDelegate{chosen: m.chosen})
+	m.title, m.help = "Select worktrees", multiHelp
+	m.setQuery(query)
+	return m
+}
+
+func (m *model) setQuery(q string) {
+	m.query = q
+	m.jump = ""
+	if q == "" {
+		m.list.ResetFilter()
+		m.list.Title = m.title + " (" + m.help + ")"
+		return
+	}
+	m.list.SetFilterText(q)
+	m.list.Title = m.title + ": " + q
+}
+
+func (m model) Init() tea.Cmd { return m.startPreview() }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	load := m.startPreview()
+	if cmd == nil {
+		return m, load
+	}
+	return m, tea.Batch(cmd, load)
+}
+
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case previewMsg:
+		m.previews[msg.path] = previewResult{preview: msg.preview, err: msg.err}
+		return m, nil
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q", "esc":
+		if m.chosen != nil && !m.filtering && msg.Type != tea.KeyCtrlC {
+			if next, cmd, ok := m.multiKey(msg); ok {
+				return next, cmd
+			}
+			break
+		}
+		switch msg.Type {
+		case tea.KeyCtrlC:
 			m.cancelled = true
 			return m, tea.Quit
-		case "enter":
+		case tea.KeyEsc:
+			if m.filtering {
+				m.filtering = false
+				m.setQuery("")
+				return m, nil
+			}
+			if m.query != "" {
+				m.setQuery("")
+				return m, nil
+			}
+			m.cancelled = true
+			return m, tea.Quit
+		case tea.KeyEnter:
+			if m.filtering {
+				m.filtering = false
+				return m, nil
+			}
 			if item, ok := m.list.SelectedItem().(worktreeItem); ok {
 				m.selected = &item.info
 				return m, tea.Quit
 			}
-		default:
-			if idx, err := parseDigit(msg.String()); err == nil {
-				if idx >= 0 && idx < len(m.list.Items()) {
-					m.list.Select(idx)
+			return m, nil
+		case tea.KeyBackspace:
+			if r := []rune(m.query); len(r) > 0 {
+				m.setQuery(string(r[:len(r)-1]))
+			}
+			return m, nil
+		case tea.KeyRunes, tea.KeySpace:
+			s := string(msg.Runes)
+			if msg.Type == tea.KeySpace {
+				s = " "
+			}
+			// クエリが空のときだけ数字をジャンプに使う。
+			if m.query == "" {
+				if _, err := parseDigit(s); err == nil {
+					m.jumpTo(s)
+					return m, nil
 				}
 			}
+			m.setQuery(m.query + s)
+			return m, nil
 		}
+		m.jump = ""
 	case tea.WindowSizeMsg:
 		// Bubble Teaが端末サイズを送ってきたときにリストの表示幅・高さを更新する。
 		// 幅が0のままだとデリゲートが何も描画しないため、文字が見えなくなる。
-		m.list.SetSize(msg.Width, msg.Height)
+		m.width, m.height = msg.Width, msg.Height
+		m.list.SetSize(m.listSize())
 	}
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
 	return m, cmd
 }
 
+// multiKey handles keys in multi-select mode outside filtering. ok is false
+// for keys left to the list (cursor movement).
+func (m model) multiKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.query != "" {
+			m.setQuery("")
+			return m, nil, true
+		}
+		m.cancelled = true
+		return m, tea.Quit, true
+	case tea.KeyEnter:
+		for _, it := range m.list.Items() {
+			if wt := it.(worktreeItem).info; m.chosen[wt.Path] {
+				m.picked = append(m.picked, wt)
+			}
+		}
+		if len(m.picked) == 0 {
+			if item, ok := m.list.SelectedItem().(worktreeItem); ok {
+				m.picked = []domain.WorktreeInfo{item.info}
+			}
+		}
+		return m, tea.Quit, true
+	case tea.KeySpace:
+		if item, ok := m.list.SelectedItem().(worktreeItem); ok {
+			m.toggle(item.info.Path, !m.chosen[item.info.Path])
+			m.list.CursorDown()
+		}
+		return m, nil, true
+	case tea.KeyRunes:
+		s := string(msg.Runes)
+		switch s {
+		case "/":
+			m.filtering = true
+		case "a":
+			// 絞り込み結果がすべて選択済みなら解除、そうでなければ全選択。
+			visible := m.list.VisibleItems()
+			all := true
+			for _, it := range visible {
+				all = all && m.chosen[it.(worktreeItem).info.Path]
+			}
+			for _, it := range visible {
+				m.toggle(it.(worktreeItem).info.Path, !all)
+			}
+		default:
+			if _, err := parseDigit(s); err == nil {
+				m.jumpTo(s)
+			}
+		}
+		return m, nil, true
+	}
+	m.jump = ""
+	return m, nil, false
+}
+
+func (m model) toggle(path string, on bool) {
+	if on {
+		m.chosen[path] = true
+	} else {
+		delete(m.chosen, path)
+	}
+}
+
+// jumpTo extends the pending jump with digit ("1" then "2" selects 12) and
+// starts over from digit when the longer number is out of range.
+func (m *model) jumpTo(digit string) {
+	n := len(m.list.VisibleItems())
+	next := m.jump + digit
+	if idx, _ := strconv.Atoi(next); idx >= n {
+		next = digit
+	}
+	idx, _ := strconv.Atoi(next)
+	if idx >= n {
+		m.jump = ""
+		return
+	}
+	m.jump = next
+	m.list.Select(idx)
+}
+
 func (m model) View() string {
-	return m.list.View()
+	if m.preview == nil {
+		return m.list.View()
+	}
+	w, h := m.paneSize()
+	var res previewResult
+	if item, ok := m.list.SelectedItem().(worktreeItem); ok {
+		res = m.previews[item.info.Path]
+	}
+	pane := renderPreview(res, w, h)
+	if m.sidePane() {
+		lw, _ := m.listSize()
+		return lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(lw).Render(m.list.View()), pane)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.list.View(), pane)
 }
 
 type worktreeItem struct {
@@ -97,11 +364,71 @@ func (i worktreeItem) Title() string {
 	if i.info.IsCurrent {
 		current = " *"
 	}
-	return fmt.Sprintf("%s (%s)%s", i.info.Path, i.info.Branch, current)
+	flags := ""
+	for _, f := range i.info.Flags() {
+		if f == "bare" || f == "detached" {
+			continue
+		}
+		flags += " [" + f + "]"
+	}
+	return i.FilterValue() + flags + current
 }
 
 func (i worktreeItem) Description() string { return "" }
-func (i worktreeItem) FilterValue() string { return i.info.Path }
+
+// FilterValue is the leading part of Title, so match positions can be
+// highlighted in the title directly.
+func (i worktreeItem) FilterValue() string {
+	branch := i.info.Branch
+	switch {
+	case i.info.Bare:
+		branch = "bare"
+	case branch == "":
+		branch = "detached"
+	}
+	return fmt.Sprintf("%s (%s)", i.info.Path, branch)
+}
+
+// itemDelegate renders one line per worktree with its jump number and the
+// fuzzy matches highlighted. A non-nil chosen adds multi-select checkboxes.
+type itemDelegate struct {
+	chosen map[string]bool
+}
+
+func (itemDelegate) Height() int                         { return 1 }
+func (itemDelegate) Spacing() int                        { return 0 }
+func (itemDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	it, ok := item.(worktreeItem)
+	if !ok {
+		return
+	}
+	style, cursor := normalStyle, "  "
+	if index == m.Index() {
+		style, cursor = selectedStyle, "> "
+	}
+	title := it.Title()
+	if m.IsFiltered() {
+		title = lipgloss.StyleRunes(title, m.MatchesForItem(index), matchStyle.Inherit(style), style)
+	} else {
+		title = style.Render(title)
+	}
+	width := len(strconv.Itoa(max(len(m.VisibleItems())-1, 0)))
+	if d.chosen != nil {
+		box := "[ ] "
+		if d.chosen[it.info.Path] {
+			box = "[x] "
+		}
+		cursor += box
+	}
+	line := cursor + numberStyle.Render(fmt.Sprintf("%*d", width, index)) + " " + title
+	if m.Width() > 0 {
+		// プレビューを横に並べるときに折り返さないよう切り詰める。
+		line = lipgloss.NewStyle().MaxWidth(m.Width()).Render(line)
+	}
+	fmt.Fprint(w, line)
+}
 
 func parseDigit(s string) (int, error) {
 	s = strings.TrimSpace(s)
@@ -110,3 +437,113 @@ func parseDigit(s string) (int, error) {
 	}
 	return strconv.Atoi(s)
 }
+
+// previewResult is a cached preview; a zero value with loading unset means
+// nothing was requested yet.
+type previewResult struct {
+	preview domain.WorktreePreview
+	err     error
+	loading bool
+}
+
+type previewMsg struct {
+	path    string
+	preview domain.WorktreePreview
+	err     error
+}
+
+// startPreview marks the highlighted worktree's preview as loading and
+// returns the command that loads it; nil when it is cached or already loading.
+func (m *model) startPreview() tea.Cmd {
+	if m.preview == nil {
+		return nil
+	}
+	item, ok := m.list.SelectedItem().(worktreeItem)
+	if !ok {
+		return nil
+	}
+	wt := item.info
+	if _, ok := m.previews[wt.Path]; ok {
+		return nil
+	}
+	m.previews[wt.Path] = previewResult{loading: true}
+	load := m.preview
+	return func() tea.Msg {
+		p, err := load(wt)
+		return previewMsg{path: wt.Path, preview: p, err: err}
+	}
+}
+
+// sidePane reports whether the preview goes to the right of the list
+// (wide terminals) rather than below it.
+func (m model) sidePane() bool { return m.width >= 100 }
+
+func (m model) listSize() (int, int) {
+	if m.preview == nil {
+		return m.width, m.height
+	}
+	w, h := m.paneSize()
+	if m.sidePane() {
+		return m.width - w, m.height
+	}
+	return m.width, m.height - h
+}
+
+func (m model) paneSize() (int, int) {
+	if m.sidePane() {
+		return m.width * 45 / 100, m.height
+	}
+	return m.width, min(10, m.height/2)
+}
+
+var (
+	paneStyle  = lipgloss.NewStyle().Padding(0, 1)
+	labelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	headStyle  = lipgloss.NewStyle().Bold(true)
+)
+
+func renderPreview(res previewResult, width, height int) string {
+	var lines []string
+	switch {
+	case res.loading:
+		lines = append(lines, labelStyle.Render("loading..."))
+	case res.err != nil:
+		lines = append(lines, "error: "+res.err.Error())
+	case res.preview.Worktree.Path != "":
+		lines = previewLines(res.preview)
+	}
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	return paneStyle.Width(max(width, 0)).MaxWidth(max(width, 0)).Render(strings.Join(lines, "\n"))
+}
+
+func previewLines(p domain.WorktreePreview) []string {
+	wt := p.Worktree
+	label := func(s string) string { return labelStyle.Render(fmt.Sprintf("%-9s", s)) }
+
+	lines := []string{headStyle.Render(worktreeItem{info: wt}.FilterValue())}
+	if c := p.LastCommit; c.Hash != "" {
+		lines = append(lines, label("commit")+c.ShortHash()+" "+c.Subject)
+		lines = append(lines, label("author")+c.Author+", "+c.Date.Local().Format("2006-01-02 15:04"))
+	}
+	upstream := "none"
+	if wt.Upstream != "" {
+		upstream = fmt.Sprintf("%s ↑%d ↓%d", wt.Upstream, wt.Ahead, wt.Behind)
+	}
+	lines = append(lines, label("upstream")+upstream)
+	session := "none"
+	if p.HasSession {
+		session = fmt.Sprintf("running (%d window(s))", p.Windows)
+	}
+	lines = append(lines, label("session")+session)
+	if len(p.Status) == 0 {
+		lines = append(lines, label("status")+"clean")
+		return lines
+	}
+	lines = append(lines, label("status")+fmt.Sprintf("%d change(s)", len(p.Status)))
+	for _, s := range p.Status {
+		lines = append(lines, "  "+s)
+	}
+	return lines
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -62,3 +63,180 @@ func TestParseDigit(t *testing.T) {
 		}
 	}
 }
+
+func pickerFixture() []domain.WorktreeInfo {
+	wts := []domain.WorktreeInfo{
+		{Path: "/repo", Branch: "main"},
+		{Path: "/repo/worktrees/feature-login", Branch: "feature/login"},
+		{Path: "/repo/worktrees/fix-typo", Branch: "fix/typo"},
+	}
+	for i := 0; i < 10; i++ {
+		b := "chore/task" + string(rune('a'+i))
+		wts = append(wts, domain.WorktreeInfo{Path: "/repo/worktrees/" + b, Branch: b})
+	}
+	return wts
+}
+
+func typeKeys(m model, s string) model {
+	for _, r := range s {
+		mAny, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = mAny.(model)
+	}
+	return m
+}
+
+func TestFilter(t *testing.T) {
+	got := Filter(pickerFixture(), "login")
+	if len(got) != 1 || got[0].Branch != "feature/login" {
+		t.Fatalf("Filter(login) = %+v", got)
+	}
+	if got := Filter(pickerFixture(), "fxtpo"); len(got) != 1 || got[0].Branch != "fix/typo" {
+		t.Fatalf("fuzzy match failed: %+v", got)
+	}
+}
+
+func TestModelTypeToFilter(t *testing.T) {
+	m := newModel(pickerFixture(), "")
+	m = typeKeys(m, "typo")
+	if m.query != "typo" || len(m.list.VisibleItems()) != 1 {
+		t.Fatalf("query=%q visible=%d", m.query, len(m.list.VisibleItems()))
+	}
+	if m.list.MatchesForItem(0) == nil {
+		t.Fatal("expected match positions for highlighting")
+	}
+
+	mAny, _ := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = mAny.(model)
+	if m.query != "typ" {
+		t.Fatalf("backspace left %q", m.query)
+	}
+	mAny, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = mAny.(model)
+	if m.query != "" || m.cancelled || len(m.list.VisibleItems()) != 13 {
+		t.Fatalf("esc should clear the filter first: query=%q cancelled=%v", m.query, m.cancelled)
+	}
+	mAny, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !mAny.(model).cancelled {
+		t.Fatal("esc on empty query should cancel")
+	}
+}
+
+func TestModelMultiDigitJump(t *testing.T) {
+	m := newModel(pickerFixture(), "")
+	m = typeKeys(m, "12")
+	if m.list.Index() != 12 || m.query != "" {
+		t.Fatalf("jump to 12 failed: index=%d query=%q", m.list.Index(), m.query)
+	}
+	// 13 は範囲外なので 3 からやり直す。
+	m = typeKeys(m, "3")
+	if m.list.Index() != 3 {
+		t.Fatalf("out-of-range jump should restart, index=%d", m.list.Index())
+	}
+}
+
+func TestNewModelWithInitialQuery(t *testing.T) {
+	m := newModel(pickerFixture(), "chore")
+	if len(m.list.VisibleItems()) != 10 {
+		t.Fatalf("visible=%d, want 10", len(m.list.VisibleItems()))
+	}
+	// クエリ入力中の数字はジャンプではなくフィルタになる。
+	if got := typeKeys(m, "1"); got.query != "chore1" {
+		t.Fatalf("digit should extend the query, got %q", got.query)
+	}
+	mAny, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if sel := mAny.(model).selected; sel == nil {
+		t.Fatal("enter should select the highlighted match")
+	}
+}
+
+func TestModelLoadsPreviewAsyncAndCaches(t *testing.T) {
+	calls := map[string]int{}
+	m := newModel(pickerFixture()[:2], "")
+	m.preview = func(wt domain.WorktreeInfo) (domain.WorktreePreview, error) {
+		calls[wt.Path]++
+		return domain.WorktreePreview{
+			Worktree:   wt,
+			LastCommit: domain.Commit{Hash: "abcdef123", Subject: "subject of " + wt.Branch},
+			Status:     []string{" M a.go"},
+			HasSession: true,
+			Windows:    3,
+		}, nil
+	}
+	m.previews = map[string]previewResult{}
+
+	// 読み込みはコマンドとして返され、Update 自体はブロックしない。
+	cmd := m.Init()
+	if cmd == nil || len(calls) != 0 {
+		t.Fatalf("Init should return a load command without calling it (calls=%v)", calls)
+	}
+	mAny, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	m = mAny.(model)
+	if !strings.Contains(m.View(), "loading...") {
+		t.Fatal("pane should show loading before the result arrives")
+	}
+	mAny, _ = m.Update(cmd())
+	m = mAny.(model)
+	view := m.View()
+	for _, want := range []string{"subject of main", "3 window(s)", "1 change(s)"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	mAny, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = mAny.(model)
+	if cmd == nil {
+		t.Fatal("moving to an unloaded worktree should request its preview")
+	}
+	mAny, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = mAny.(model)
+	if c := m.startPreview(); c != nil {
+		t.Fatal("cached preview should not be loaded again")
+	}
+	if calls["/repo"] != 1 {
+		t.Fatalf("preview loaded %d times", calls["/repo"])
+	}
+}
+
+func TestMultiModelTogglesAndSelectsFiltered(t *testing.T) {
+	m := newMultiModel(pickerFixture(), "")
+	press := func(k tea.KeyMsg) {
+		mAny, _ := m.Update(k)
+		m = mAny.(model)
+	}
+
+	// space で選択してカーソルが次に進む。
+	press(tea.KeyMsg{Type: tea.KeySpace})
+	if !m.chosen["/repo"] || m.list.Index() != 1 {
+		t.Fatalf("space: chosen=%v index=%d", m.chosen, m.list.Index())
+	}
+	// フィルタ外では文字入力はクエリにならない。
+	m = typeKeys(m, "x")
+	if m.query != "" {
+		t.Fatalf("typing outside filter changed query to %q", m.query)
+	}
+
+	m = typeKeys(m, "/chore")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filtering || m.query != "chore" || len(m.list.VisibleItems()) != 10 {
+		t.Fatalf("filtering=%v query=%q visible=%d", m.filtering, m.query, len(m.list.VisibleItems()))
+	}
+	m = typeKeys(m, "a")
+	if len(m.chosen) != 11 {
+		t.Fatalf("a should add all filtered worktrees, chosen=%d", len(m.chosen))
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.picked) != 11 || m.picked[0].Path != "/repo" {
+		t.Fatalf("picked %d worktrees: %+v", len(m.picked), m.picked)
+	}
+}
+
+func TestMultiModelEnterWithoutToggleReturnsHighlighted(t *testing.T) {
+	m := newMultiModel(pickerFixture(), "")
+	m = typeKeys(m, "2")
+	mAny, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = mAny.(model)
+	if len(m.picked) != 1 || m.picked[0].Branch != "fix/typo" {
+		t.Fatalf("picked = %+v", m.picked)
+	}
+}
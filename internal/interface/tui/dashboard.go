@@ -0,0 +1,454 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/example/gwm/internal/app/usecase"
+	"github.com/example/gwm/internal/domain"
+)
+
+const dashboardHelp = "enter open · c create · d remove · m rename · k kill session · e editor · r refresh · / filter · q quit"
+
+// footerHeight is the status line plus the help/prompt line.
+const footerHeight = 2
+
+var statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+
+// Dashboard is the full-screen UI started by `gwm` or `gwm ui`. Every action
+// goes through the same interactors as the corresponding CLI command.
+type Dashboard struct {
+	List *usecase.ListInteractor
+	// Create should have no Launcher so that the dashboard stays open;
+	// worktrees are opened with Cd instead.
+	Create  *usecase.CreateInteractor
+	Remove  *usecase.RemoveInteractor
+	Rename  *usecase.RenameInteractor
+	Cd      *usecase.CdInteractor
+	Session *usecase.SessionInteractor
+	// Editor opens a worktree directory; empty falls back to $VISUAL,
+	// $EDITOR and vi.
+	Editor string
+}
+
+// Run shows the dashboard and, when a worktree was chosen with Enter, opens
+// it through Cd after the UI has closed.
+func (d Dashboard) Run() error {
+	wts, err := d.List.Execute()
+	if err != nil {
+		return err
+	}
+	// UI は stderr に描画する (cd ランチャーが stdout を使うことがある)。
+	res, err := tea.NewProgram(newDashboard(d, wts), tea.WithAltScreen(), tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return err
+	}
+	final := res.(dashboard)
+	if final.open == nil {
+		return nil
+	}
+	return d.Cd.Launch(*final.open, domain.LaunchOptions{})
+}
+
+type dashMode int
+
+const (
+	modeBrowse dashMode = iota
+	modeFilter
+	modeCreate
+	modeRename
+	modeConfirm
+)
+
+type dashboard struct {
+	deps   Dashboard
+	picker model
+	mode   dashMode
+	input  textinput.Model
+	// target is the worktree being renamed, or together with inspection the
+	// one awaiting removal confirmation.
+	target     domain.WorktreeInfo
+	inspection domain.RemovalInspection
+	// busy is set while an action runs; further actions are ignored.
+	busy   bool
+	status string
+	open   *domain.WorktreeInfo
+	width  int
+}
+
+type listMsg struct {
+	list []domain.WorktreeInfo
+	err  error
+}
+
+type actionMsg struct {
+	status string
+	err    error
+	reload bool
+}
+
+type inspectMsg struct {
+	wt         domain.WorktreeInfo
+	inspection domain.RemovalInspection
+	err        error
+}
+
+func newDashboard(d Dashboard, wts []domain.WorktreeInfo) dashboard {
+	picker := newModel(wts, "")
+	picker.title, picker.help = "gwm", fmt.Sprintf("%d worktree(s)", len(wts))
+	picker.setQuery("")
+	if d.List != nil {
+		picker.preview = d.List.Preview
+		picker.previews = map[string]previewResult{}
+	}
+	input := textinput.New()
+	input.Placeholder = "feature/xyz"
+	return dashboard{deps: d, picker: picker, input: input}
+}
+
+func (m dashboard) Init() tea.Cmd { return m.picker.Init() }
+
+func (m dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m.updatePicker(tea.WindowSizeMsg{Width: msg.Width, Height: max(msg.Height-footerHeight, 0)})
+	case listMsg:
+		if msg.err != nil {
+			m.status = "error: " + msg.err.Error()
+			return m, nil
+		}
+		m.setList(msg.list)
+		return m, m.picker.startPreview()
+	case actionMsg:
+		m.busy = false
+		m.status = msg.status
+		if msg.err != nil {
+			m.status = "error: " + msg.err.Error()
+		}
+		if msg.reload {
+			return m, m.reload()
+		}
+		return m, nil
+	case inspectMsg:
+		m.busy = false
+		if msg.err != nil {
+			m.status = "error: " + msg.err.Error()
+			return m, nil
+		}
+		m.target, m.inspection = msg.wt, msg.inspection
+		m.mode = modeConfirm
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m.updatePicker(msg)
+}
+
+func (m dashboard) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	pm, cmd := m.picker.Update(msg)
+	m.picker = pm.(model)
+	return m, cmd
+}
+
+func (m dashboard) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return m.quit()
+	}
+	switch m.mode {
+	case modeCreate:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.mode = modeBrowse
+			return m, nil
+		case tea.KeyEnter:
+			m.mode = modeBrowse
+			branch := strings.TrimSpace(m.input.Value())
+			if branch == "" {
+				return m, nil
+			}
+			return m.start("creating "+branch+"...", m.createCmd(branch))
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	case modeRename:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.mode = modeBrowse
+			return m, nil
+		case tea.KeyEnter:
+			m.mode = modeBrowse
+			branch := strings.TrimSpace(m.input.Value())
+			if branch == "" || branch == m.target.ShortBranch() {
+				return m, nil
+			}
+			return m.start("renaming "+m.target.ShortBranch()+"...", m.renameCmd(m.target, branch))
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	case modeConfirm:
+		switch msg.String() {
+		case "y", "Y":
+			m.mode = modeBrowse
+			return m.start("removing "+m.target.ShortBranch()+"...", m.removeCmd(m.target, m.inspection))
+		case "n", "N", "esc", "q":
+			m.mode = modeBrowse
+			m.status = "removal cancelled"
+		}
+		return m, nil
+	case modeFilter:
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.mode = modeBrowse
+			return m, nil
+		case tea.KeyEsc:
+			m.mode = modeBrowse
+			m.picker.setQuery("")
+			return m, m.picker.startPreview()
+		}
+		return m.updatePicker(msg)
+	}
+
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyDown, tea.KeyPgUp, tea.KeyPgDown, tea.KeyHome, tea.KeyEnd:
+		return m.updatePicker(msg)
+	case tea.KeyEsc:
+		if m.picker.query != "" {
+			m.picker.setQuery("")
+			return m, m.picker.startPreview()
+		}
+		return m, nil
+	case tea.KeyEnter:
+		if wt, ok := m.selected(); ok {
+			if m.busy {
+				return m.quit()
+			}
+			m.open = &wt
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	if _, err := parseDigit(key); err == nil {
+		m.picker.jumpTo(key)
+		return m, m.picker.startPreview()
+	}
+	switch key {
+	case "q":
+		return m.quit()
+	case "/":
+		m.mode = modeFilter
+		return m, nil
+	case "r":
+		m.status = "refreshing..."
+		return m, m.reload()
+	}
+	if m.busy {
+		return m, nil
+	}
+	switch key {
+	case "c":
+		m.mode = modeCreate
+		m.input.SetValue("")
+		return m, m.input.Focus()
+	}
+	wt, ok := m.selected()
+	if !ok {
+		return m, nil
+	}
+	switch key {
+	case "d":
+		if wt.Branch == "" {
+			m.status = "error: only worktrees with a branch can be removed"
+			return m, nil
+		}
+		return m.start("inspecting "+wt.ShortBranch()+"...", m.inspectCmd(wt))
+	case "m":
+		if wt.Branch == "" {
+			m.status = "error: only worktrees with a branch can be renamed"
+			return m, nil
+		}
+		m.mode = modeRename
+		m.target = wt
+		// 今の名前から少しだけ変えることが多いので、入力欄に入れておく。
+		m.input.SetValue(wt.ShortBranch())
+		m.input.CursorEnd()
+		return m, m.input.Focus()
+	case "k":
+		return m.start("killing session...", m.killCmd(wt))
+	case "e":
+		return m, tea.ExecProcess(editorCommand(m.deps.Editor, wt.Path), func(err error) tea.Msg {
+			return actionMsg{status: "editor closed", err: err}
+		})
+	}
+	return m, nil
+}
+
+func (m dashboard) selected() (domain.WorktreeInfo, bool) {
+	item, ok := m.picker.list.SelectedItem().(worktreeItem)
+	return item.info, ok
+}
+
+// quit exits unless an action is still running; create, remove and rename
+// would be cut off halfway, leaving a partial worktree behind.
+func (m dashboard) quit() (tea.Model, tea.Cmd) {
+	if m.busy {
+		m.status = "wait for the running action to finish before quitting"
+		return m, nil
+	}
+	return m, tea.Quit
+}
+
+// start marks the dashboard busy and runs cmd in the background.
+func (m dashboard) start(status string, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m.busy = true
+	m.status = status
+	return m, cmd
+}
+
+func (m *dashboard) setList(wts []domain.WorktreeInfo) {
+	current, _ := m.selected()
+	items := make([]list.Item, len(wts))
+	for i, wt := range wts {
+		items[i] = worktreeItem{info: wt}
+	}
+	m.picker.list.SetItems(items)
+	if m.picker.previews != nil {
+		m.picker.previews = map[string]previewResult{}
+	}
+	m.picker.help = fmt.Sprintf("%d worktree(s)", len(wts))
+	// 絞り込み中なら新しい一覧にも同じクエリを当て直す。
+	m.picker.setQuery(m.picker.query)
+	for i, it := range m.picker.list.VisibleItems() {
+		if it.(worktreeItem).info.Path == current.Path {
+			m.picker.list.Select(i)
+			break
+		}
+	}
+}
+
+func (m dashboard) reload() tea.Cmd {
+	lister := m.deps.List
+	return func() tea.Msg {
+		wts, err := lister.Execute()
+		return listMsg{list: wts, err: err}
+	}
+}
+
+func (m dashboard) createCmd(branch string) tea.Cmd {
+	create := m.deps.Create
+	return func() tea.Msg {
+		out, err := create.Execute(usecase.CreateInput{Branch: branch})
+		return actionMsg{status: summary(out.Messages), err: err, reload: true}
+	}
+}
+
+func (m dashboard) inspectCmd(wt domain.WorktreeInfo) tea.Cmd {
+	remove := m.deps.Remove
+	return func() tea.Msg {
+		r, err := remove.Inspect(wt.ShortBranch())
+		return inspectMsg{wt: wt, inspection: r, err: err}
+	}
+}
+
+func (m dashboard) removeCmd(wt domain.WorktreeInfo, r domain.RemovalInspection) tea.Cmd {
+	remove := m.deps.Remove
+	// 確認画面で変更内容を見せたうえで同意を得ているので、未コミットの変更があれば強制削除する。
+	force := len(r.Changed) > 0 || len(r.Untracked) > 0
+	return func() tea.Msg {
+		out, err := remove.Execute(usecase.RemoveInput{Branch: wt.ShortBranch(), Force: force})
+		return actionMsg{status: summary(out.Messages), err: err, reload: true}
+	}
+}
+
+func (m dashboard) renameCmd(wt domain.WorktreeInfo, branch string) tea.Cmd {
+	rename := m.deps.Rename
+	return func() tea.Msg {
+		out, err := rename.Execute(usecase.RenameInput{Branch: wt.ShortBranch(), NewBranch: branch})
+		return actionMsg{status: summary(out.Messages), err: err, reload: true}
+	}
+}
+
+func (m dashboard) killCmd(wt domain.WorktreeInfo) tea.Cmd {
+	session := m.deps.Session
+	return func() tea.Msg {
+		if err := session.Kill(wt); err != nil {
+			return actionMsg{err: err}
+		}
+		return actionMsg{status: "session killed: " + wt.ShortBranch(), reload: true}
+	}
+}
+
+// summary keeps the status line to the last interactor message.
+func summary(msgs []string) string {
+	if len(msgs) == 0 {
+		return ""
+	}
+	return msgs[len(msgs)-1]
+}
+
+// editorCommand builds the command that opens path. editor may carry
+// arguments (e.g. "code -n").
+func editorCommand(editor, path string) *exec.Cmd {
+	for _, e := range []string{editor, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if strings.TrimSpace(e) != "" {
+			editor = e
+			break
+		}
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+func (m dashboard) View() string {
+	var footer string
+	switch m.mode {
+	case modeCreate:
+		footer = "new branch: " + m.input.View() + "  (enter create · esc cancel)"
+	case modeRename:
+		footer = "rename " + m.target.ShortBranch() + " to: " + m.input.View() + "  (enter rename · esc cancel)"
+	case modeFilter:
+		footer = "filter: " + m.picker.query + "█  (enter keep · esc clear)"
+	case modeConfirm:
+		return m.confirmView()
+	default:
+		footer = dashboardHelp
+	}
+	line := lipgloss.NewStyle().MaxWidth(max(m.width, 1))
+	if m.width == 0 {
+		line = lipgloss.NewStyle()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.picker.View(),
+		line.Render(statusStyle.Render(m.status)),
+		line.Render(footer),
+	)
+}
+
+func (m dashboard) confirmView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Remove %s (%s)?\n\n", headStyle.Render(m.target.ShortBranch()), m.target.Path)
+	if m.inspection.Clean() {
+		b.WriteString("  nothing would be lost\n")
+	} else {
+		b.WriteString("This will discard or interrupt:\n")
+		for _, f := range m.inspection.Findings() {
+			b.WriteString("  - " + f + "\n")
+		}
+	}
+	b.WriteString("\n[y] remove   [n] cancel")
+	return b.String()
+}
@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/example/gwm/internal/app/usecase"
+	"github.com/example/gwm/internal/domain"
+)
+
+// dashWorktrees implements the WorktreeService calls the dashboard makes;
+// anything else panics through the nil embedded interface.
+type dashWorktrees struct {
+	domain.WorktreeService
+	list    []domain.WorktreeInfo
+	added   []string
+	removed []string
+	renamed []string
+	forced  bool
+	dirty   bool
+}
+
+func (s *dashWorktrees) ListWorktrees() ([]domain.WorktreeInfo, error) { return s.list, nil }
+func (s *dashWorktrees) BranchExists(string) (bool, error)             { return false, nil }
+func (s *dashWorktrees) CreateBranch(string, string, bool) (string, error) {
+	return "main", nil
+}
+func (s *dashWorktrees) AddWorktree(branch string) (string, error) {
+	s.added = append(s.added, branch)
+	s.list = append(s.list, domain.WorktreeInfo{Branch: branch, Path: "/repo/worktrees/" + branch})
+	return "/repo/worktrees/" + branch, nil
+}
+func (s *dashWorktrees) Describe(wt domain.WorktreeInfo) (domain.WorktreeInfo, error) {
+	return wt, nil
+}
+func (s *dashWorktrees) Inspect(wt domain.WorktreeInfo) (domain.RemovalInspection, error) {
+	r := domain.RemovalInspection{Branch: wt.Branch, Path: wt.Path}
+	if s.dirty {
+		r.Changed = []string{"main.go"}
+	}
+	return r, nil
+}
+func (s *dashWorktrees) RenameWorktree(wt domain.WorktreeInfo, branch string) (string, error) {
+	s.renamed = append(s.renamed, wt.ShortBranch()+" -> "+branch)
+	for i := range s.list {
+		if s.list[i].Path == wt.Path {
+			s.list[i] = domain.WorktreeInfo{Branch: "refs/heads/" + branch, Path: "/repo/worktrees/" + branch}
+		}
+	}
+	return "/repo/worktrees/" + branch, nil
+}
+func (s *dashWorktrees) RemoveWorktree(branch string, force bool) (string, error) {
+	s.removed = append(s.removed, branch)
+	s.forced = force
+	return "/repo/worktrees/" + branch, nil
+}
+
+type nopConfig struct{}
+
+func (nopConfig) Load() ([]domain.ConfigEntry, error) { return nil, nil }
+func (nopConfig) Save([]domain.ConfigEntry) error     { return nil }
+
+type nopFiles struct{}
+
+func (nopFiles) Deploy([]domain.ConfigEntry, string, domain.TemplateVars) error { return nil }
+func (nopFiles) Expand(domain.ConfigEntry) ([]string, error)                    { return nil, nil }
+
+func newTestDashboard(wt *dashWorktrees) dashboard {
+	d := Dashboard{
+		List:   &usecase.ListInteractor{Worktrees: wt},
+		Create: &usecase.CreateInteractor{Worktrees: wt, Config: nopConfig{}, FileOps: nopFiles{}},
+		Remove: &usecase.RemoveInteractor{Worktrees: wt},
+		Rename: &usecase.RenameInteractor{Worktrees: wt},
+	}
+	m := newDashboard(d, wt.list)
+	m.picker.preview = nil // プレビューは cd_test で確認済み
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	return next.(dashboard)
+}
+
+// drive feeds msg and runs the returned commands until none is left,
+// like the Bubble Tea runtime would. Commands that do not finish promptly
+// (cursor blink timers) are dropped.
+func drive(t *testing.T, m dashboard, msg tea.Msg) dashboard {
+	t.Helper()
+	queue := []tea.Msg{msg}
+	for len(queue) > 0 {
+		next, cmd := m.Update(queue[0])
+		m = next.(dashboard)
+		queue = queue[1:]
+		if cmd == nil {
+			continue
+		}
+		done := make(chan tea.Msg, 1)
+		go func() { done <- cmd() }()
+		select {
+		case out := <-done:
+			if _, batch := out.(tea.BatchMsg); out != nil && !batch {
+				queue = append(queue, out)
+			}
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return m
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestDashboardCreate(t *testing.T) {
+	wt := &dashWorktrees{list: []domain.WorktreeInfo{{Branch: "main", Path: "/repo"}}}
+	m := newTestDashboard(wt)
+
+	m = drive(t, m, key("c"))
+	if m.mode != modeCreate {
+		t.Fatalf("c should open the branch prompt, mode=%v", m.mode)
+	}
+	for _, r := range "feat" {
+		m = drive(t, m, key(string(r)))
+	}
+	m = drive(t, m, key("enter"))
+	if len(wt.added) != 1 || wt.added[0] != "feat" {
+		t.Fatalf("worktree not created: %v", wt.added)
+	}
+	if m.busy || len(m.picker.list.Items()) != 2 {
+		t.Fatalf("list not reloaded after create: busy=%v items=%d", m.busy, len(m.picker.list.Items()))
+	}
+	if !strings.Contains(m.View(), "feat") {
+		t.Fatalf("new worktree missing from view:\n%s", m.View())
+	}
+}
+
+func TestDashboardRemoveAsksWithFindings(t *testing.T) {
+	wt := &dashWorktrees{
+		list:  []domain.WorktreeInfo{{Branch: "main", Path: "/repo"}, {Branch: "feat", Path: "/repo/worktrees/feat"}},
+		dirty: true,
+	}
+	m := newTestDashboard(wt)
+	m = drive(t, m, key("1"))
+	m = drive(t, m, key("d"))
+	if m.mode != modeConfirm || !strings.Contains(m.View(), "1 uncommitted change(s): main.go") {
+		t.Fatalf("expected confirmation with findings, mode=%v view:\n%s", m.mode, m.View())
+	}
+
+	m = drive(t, m, key("n"))
+	if len(wt.removed) != 0 || m.mode != modeBrowse {
+		t.Fatalf("n should cancel: removed=%v", wt.removed)
+	}
+
+	m = drive(t, m, key("d"))
+	m = drive(t, m, key("y"))
+	if len(wt.removed) != 1 || wt.removed[0] != "feat" || !wt.forced {
+		t.Fatalf("unexpected removal: %v force=%v", wt.removed, wt.forced)
+	}
+}
+
+func TestDashboardRename(t *testing.T) {
+	wt := &dashWorktrees{list: []domain.WorktreeInfo{{Branch: "main", Path: "/repo"}, {Branch: "refs/heads/feat", Path: "/repo/worktrees/feat"}}}
+	m := newTestDashboard(wt)
+	m = drive(t, m, key("1"))
+
+	m = drive(t, m, key("m"))
+	if m.mode != modeRename || m.input.Value() != "feat" {
+		t.Fatalf("m should prompt with the current name, mode=%v input=%q", m.mode, m.input.Value())
+	}
+	m = drive(t, m, key("esc"))
+	if m.mode != modeBrowse || len(wt.renamed) != 0 {
+		t.Fatalf("esc should cancel: mode=%v renamed=%v", m.mode, wt.renamed)
+	}
+
+	m = drive(t, m, key("m"))
+	m = drive(t, m, key("2"))
+	m = drive(t, m, key("enter"))
+	if len(wt.renamed) != 1 || wt.renamed[0] != "feat -> feat2" {
+		t.Fatalf("unexpected rename: %v", wt.renamed)
+	}
+	if m.busy || !strings.Contains(m.View(), "feat2") {
+		t.Fatalf("list not reloaded after rename: busy=%v view:\n%s", m.busy, m.View())
+	}
+}
+
+func TestDashboardEnterOpensSelected(t *testing.T) {
+	wt := &dashWorktrees{list: []domain.WorktreeInfo{{Branch: "main", Path: "/repo"}, {Branch: "feat", Path: "/repo/worktrees/feat"}}}
+	m := newTestDashboard(wt)
+
+	// "/" で絞り込みに入ると文字キーは操作ではなく検索語になる。
+	m = drive(t, m, key("/"))
+	m = drive(t, m, key("f"))
+	m = drive(t, m, key("e"))
+	m = drive(t, m, key("enter"))
+	if m.mode != modeBrowse || m.picker.query != "fe" {
+		t.Fatalf("filter not kept: mode=%v query=%q", m.mode, m.picker.query)
+	}
+	next, cmd := m.Update(key("enter"))
+	m = next.(dashboard)
+	if m.open == nil || m.open.Branch != "feat" || cmd == nil {
+		t.Fatalf("enter should quit with the filtered worktree, got %+v", m.open)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+	if cmd := editorCommand("code -n", "/repo"); strings.Join(cmd.Args, " ") != "code -n /repo" {
+		t.Fatalf("configured editor ignored: %v", cmd.Args)
+	}
+	if cmd := editorCommand("", "/repo"); strings.Join(cmd.Args, " ") != "nano /repo" {
+		t.Fatalf("$EDITOR ignored: %v", cmd.Args)
+	}
+}
+
+func TestDashboardDoesNotQuitWhileBusy(t *testing.T) {
+	wt := &dashWorktrees{list: []domain.WorktreeInfo{{Branch: "main", Path: "/repo"}}}
+	m := newTestDashboard(wt)
+	next, _ := m.start("creating feat...", nil)
+	m = next.(dashboard)
+
+	for _, k := range []tea.KeyMsg{key("q"), {Type: tea.KeyCtrlC}, key("enter")} {
+		next, cmd := m.Update(k)
+		m = next.(dashboard)
+		if cmd != nil || m.open != nil {
+			t.Fatalf("%s should not quit while busy", k)
+		}
+	}
+	if !strings.Contains(m.status, "wait") {
+		t.Fatalf("status should explain why, got %q", m.status)
+	}
+
+	next, _ = m.Update(actionMsg{status: "done"})
+	m = next.(dashboard)
+	if _, cmd := m.Update(key("q")); cmd == nil {
+		t.Fatal("q should quit once the action finished")
+	}
+}